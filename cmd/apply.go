@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	applyFile    string
+	applyPlanOut string
+	applyPlan    string
+	applyApprove bool
+
+	// planConcurrency and planFailFast are shared by apply and import
+	planConcurrency int
+	planFailFast    bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a manifest of secrets",
+	Long: `Apply a manifest of secrets to AWS SSM Parameter Store.
+
+apply first computes a plan (what would be created, updated or deleted) by
+comparing the manifest against what's currently stored, and shows it:
+
+  + create     /path   (new secret)
+  ~ update     /path   (value changes)
+  ~ update     /path (tags only)   (a manifest tag is missing or differs)
+  - delete     /path
+  = unchanged  /path
+
+Tags on a secret that aren't in the manifest are left alone.
+
+In a terminal you're then asked to confirm before anything is changed. Pass
+--auto-approve to skip the prompt (e.g. in CI); without a terminal, nothing is
+changed unless --auto-approve is given.
+
+The plan can be saved with --plan-out for review and later applied exactly
+with --plan. Plan files contain secret values and are written with 0600
+permissions - treat them like the secrets themselves.
+
+Afterwards a summary counts what was created, updated, unchanged, deleted,
+skipped and failed, and lists each failure. With --output json (or yaml) the
+plan and progress go to stderr and only the summary is printed on stdout.
+With --emit-metrics, the secrets created, updated and deleted, and the
+changes that failed, are published as the apply command's metric.
+
+Manifest format (YAML):
+  secrets:
+    - path: db/password        # relative paths use prefix/env
+      value: hunter2
+      tags:
+        owner: platform
+  delete:
+    - old/api-key
+
+Examples:
+  # Show what would change
+  lockr apply --file manifest.yaml
+
+  # Review the plan, confirm, then apply
+  lockr apply --file manifest.yaml
+
+  # Apply without asking (CI)
+  lockr apply --file manifest.yaml --auto-approve
+
+  # Save a plan for review, then apply exactly that plan
+  lockr apply --file manifest.yaml --plan-out plan.json
+  lockr apply --plan plan.json --auto-approve
+
+  # Apply in CI and keep the summary
+  lockr apply --file manifest.yaml --auto-approve --output json > summary.json`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVar(&applyFile, "file", "", "manifest file (YAML)")
+	applyCmd.Flags().StringVar(&applyPlanOut, "plan-out", "", "write the computed plan to a file instead of applying")
+	applyCmd.Flags().StringVar(&applyPlan, "plan", "", "apply a previously saved plan file")
+	applyCmd.Flags().BoolVar(&applyApprove, "auto-approve", false, "execute the plan without asking for confirmation")
+	applyCmd.Flags().BoolVar(&applyApprove, "approve", false, "alias for --auto-approve")
+	applyCmd.Flags().IntVar(&planConcurrency, "concurrency", 5, "maximum number of changes applied at once")
+	applyCmd.Flags().BoolVar(&planFailFast, "fail-fast", false, "stop starting new changes after the first failure")
+}
+
+var (
+	planCreateStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#1A7F37", Dark: "#3FB950"})
+	planUpdateStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#9A6700", Dark: "#D29922"})
+	planDeleteStyle    = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#CF222E", Dark: "#F85149"})
+	planUnchangedStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#9B9B9B", Dark: "#5C5C5C"})
+)
+
+// manifest is the desired state read from an apply manifest file
+type manifest struct {
+	Secrets []manifestSecret `yaml:"secrets"`
+	Delete  []string         `yaml:"delete"`
+}
+
+type manifestSecret struct {
+	Path  string            `yaml:"path"`
+	Value string            `yaml:"value"`
+	Tags  map[string]string `yaml:"tags"`
+}
+
+// applyPlanFile is a computed set of changes, as saved by --plan-out
+type applyPlanFile struct {
+	Changes []planChange `json:"changes"`
+}
+
+type planChange struct {
+	Action string            `json:"action"` // create, update, delete, unchanged
+	Path   string            `json:"path"`
+	Value  string            `json:"value,omitempty"`
+	Tags   map[string]string `json:"tags,omitempty"`
+
+	// TagsOnly marks an update where the value is current but tags differ,
+	// so only the tags are set and no new version is written
+	TagsOnly bool `json:"tags_only,omitempty"`
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	switch {
+	case applyFile == "" && applyPlan == "":
+		return fmt.Errorf("specify --file or --plan")
+	case applyFile != "" && applyPlan != "":
+		return fmt.Errorf("--file and --plan are mutually exclusive")
+	case applyPlan != "" && applyPlanOut != "":
+		return fmt.Errorf("--plan-out can only be used with --file")
+	}
+
+	// Every path is resolved before the client is created, since they pick
+	// its profile
+	var p *applyPlanFile
+	var m *manifest
+	var err error
+	if applyPlan != "" {
+		if p, err = loadPlan(applyPlan); err != nil {
+			return err
+		}
+		for _, c := range p.Changes {
+			if _, err := buildPath(c.Path); err != nil {
+				return err
+			}
+		}
+	} else {
+		if m, err = loadManifest(applyFile); err != nil {
+			return err
+		}
+		if err := resolveManifest(m); err != nil {
+			return err
+		}
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	if m != nil {
+		var planErr error
+		_ = spinner.New().
+			Title("Computing plan...").
+			Action(func() {
+				p, planErr = computePlan(client, m)
+			}).
+			Run()
+
+		if planErr != nil {
+			fmt.Println(ui.Error("Failed to compute plan"))
+			return fmt.Errorf("failed to compute plan: %w", planErr)
+		}
+	}
+
+	printPlan(p)
+
+	if applyPlanOut != "" {
+		if err := savePlan(applyPlanOut, p); err != nil {
+			return err
+		}
+		fmt.Println(ui.Successf("Plan written to %s", applyPlanOut))
+		fmt.Println(ui.Subtle("Apply it with: lockr apply --plan " + applyPlanOut + " --auto-approve"))
+		return nil
+	}
+
+	return runPlan(client, "apply", p, applyApprove)
+}
+
+// runPlan executes p for command (apply or import) if it has any changes:
+// directly when approve (or assume_yes) is set, after a confirmation prompt
+// in a terminal, and not at all otherwise
+func runPlan(client *ssm.Client, command string, p *applyPlanFile, approve bool) error {
+	if planConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if !planHasChanges(p) {
+		if cfg.Output != "text" {
+			return printStructured(batchSummary{Unchanged: len(p.Changes), Failures: []batchFailure{}})
+		}
+		fmt.Println(ui.Success("No changes"))
+		return nil
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	if !skipConfirm(approve) {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Fprintln(planOut(), ui.Info("No changes made. Re-run with --auto-approve to apply."))
+			return nil
+		}
+
+		var confirmed bool
+		confirm := huh.NewConfirm().
+			Title("Apply these changes?").
+			Value(&confirmed)
+		confirm.WithTheme(ui.Theme())
+		if err := confirm.Run(); err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(planOut(), ui.Info("Cancelled"))
+			return nil
+		}
+		fmt.Fprintln(planOut())
+	}
+
+	return executePlan(client, command, p)
+}
+
+func loadManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	for i, s := range m.Secrets {
+		if s.Path == "" {
+			return nil, fmt.Errorf("manifest secret #%d has no path", i+1)
+		}
+	}
+
+	return &m, nil
+}
+
+// resolveManifest replaces the manifest's paths with full paths (see
+// buildPath)
+func resolveManifest(m *manifest) error {
+	for i := range m.Secrets {
+		path, err := buildPath(m.Secrets[i].Path)
+		if err != nil {
+			return err
+		}
+		m.Secrets[i].Path = path
+	}
+	for i := range m.Delete {
+		path, err := buildPath(m.Delete[i])
+		if err != nil {
+			return err
+		}
+		m.Delete[i] = path
+	}
+	return nil
+}
+
+func loadPlan(path string) (*applyPlanFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var p applyPlanFile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+func savePlan(path string, p *applyPlanFile) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// computePlan compares the manifest against the current state without
+// changing anything. The manifest's paths must already be resolved with
+// resolveManifest.
+func computePlan(client *ssm.Client, m *manifest) (*applyPlanFile, error) {
+	p := &applyPlanFile{}
+
+	for _, s := range m.Secrets {
+		path := s.Path
+
+		unchanged, err := client.Unchanged(path, s.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if unchanged {
+			tagsCurrent, err := tagsApplied(client, path, s.Tags)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			if tagsCurrent {
+				p.Changes = append(p.Changes, planChange{Action: "unchanged", Path: path})
+			} else {
+				p.Changes = append(p.Changes, planChange{Action: "update", Path: path, Tags: s.Tags, TagsOnly: true})
+			}
+			continue
+		}
+
+		exists, err := client.Exists(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		action := "create"
+		if exists {
+			action = "update"
+		}
+		p.Changes = append(p.Changes, planChange{Action: action, Path: path, Value: s.Value, Tags: s.Tags})
+	}
+
+	for _, path := range m.Delete {
+		exists, err := client.Exists(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if exists {
+			p.Changes = append(p.Changes, planChange{Action: "delete", Path: path})
+		}
+	}
+
+	return p, nil
+}
+
+// tagsApplied reports whether path already has every tag in tags with the
+// same value. Tags not in the manifest are left alone, so they don't count.
+func tagsApplied(client *ssm.Client, path string, tags map[string]string) (bool, error) {
+	if len(tags) == 0 {
+		return true, nil
+	}
+	current, err := client.GetTags(path)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range tags {
+		if old, ok := current[k]; !ok || old != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func planHasChanges(p *applyPlanFile) bool {
+	for _, c := range p.Changes {
+		if c.Action != "unchanged" {
+			return true
+		}
+	}
+	return false
+}
+
+func printPlan(p *applyPlanFile) {
+	w := planOut()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.SectionHeader("Plan"))
+	fmt.Fprintln(w)
+
+	counts := make(map[string]int)
+	for _, c := range p.Changes {
+		counts[c.Action]++
+		fmt.Fprintln(w, "  "+planLine(c))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.Infof("%d to create, %d to update, %d to delete, %d unchanged",
+		counts["create"], counts["update"], counts["delete"], counts["unchanged"]))
+	fmt.Fprintln(w)
+}
+
+// planOut is where the plan and each change's outcome are printed: stdout,
+// or stderr with --output json/yaml so stdout only has the summary
+func planOut() io.Writer {
+	if cfg.Output != "text" {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// planLine renders a change Terraform-plan style: a colored symbol and action
+// followed by the path
+func planLine(c planChange) string {
+	symbol, style := "?", lipgloss.NewStyle()
+	switch c.Action {
+	case "create":
+		symbol, style = "+", planCreateStyle
+	case "update":
+		symbol, style = "~", planUpdateStyle
+	case "delete":
+		symbol, style = "-", planDeleteStyle
+	case "unchanged":
+		symbol, style = "=", planUnchangedStyle
+	}
+	line := style.Render(fmt.Sprintf("%s %-9s", symbol, c.Action)) + " " + c.Path
+	if c.TagsOnly {
+		line += " " + ui.Subtle("(tags only)")
+	}
+	return line
+}
+
+// executePlan applies the changes with up to --concurrency at a time,
+// continuing past failures unless --fail-fast is set, then prints each
+// change's outcome in plan order and a batchSummary (as JSON/YAML with
+// --output)
+func executePlan(client *ssm.Client, command string, p *applyPlanFile) error {
+	auto := newAutoTagger(client, false)
+	errs := make([]error, len(p.Changes))
+	done := make([]bool, len(p.Changes))
+	var stopped atomic.Bool
+	_ = spinner.New().
+		Title("Applying changes...").
+		Action(func() {
+			sem := make(chan struct{}, planConcurrency)
+			var wg sync.WaitGroup
+			for i, c := range p.Changes {
+				if c.Action == "unchanged" {
+					continue
+				}
+				sem <- struct{}{}
+				if stopped.Load() {
+					<-sem
+					break
+				}
+				wg.Add(1)
+				go func(i int, c planChange) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					errs[i] = applyChange(client, auto, c)
+					done[i] = true
+					if errs[i] != nil && planFailFast {
+						stopped.Store(true)
+					}
+				}(i, c)
+			}
+			wg.Wait()
+		}).
+		Run()
+
+	summary := batchSummary{Failures: []batchFailure{}}
+	w := planOut()
+	for i, c := range p.Changes {
+		switch {
+		case c.Action == "unchanged":
+			summary.Unchanged++
+		case !done[i]:
+			summary.Skipped++
+			fmt.Fprintln(w, ui.Warningf("Skipped %s %s", c.Action, c.Path))
+		case errs[i] != nil:
+			summary.fail(c.Path, c.Action, errs[i])
+			fmt.Fprintln(w, ui.Errorf("Failed to %s %s: %v", c.Action, c.Path, errs[i]))
+		default:
+			summary.succeeded(c.Action)
+			fmt.Fprintln(w, ui.Successf("%s %s", c.Action, c.Path))
+		}
+	}
+	fmt.Fprintln(w)
+	emitMetrics(client, command, summary.Created+summary.Updated+summary.Deleted, summary.Failed)
+
+	if cfg.Output != "text" {
+		if err := printStructured(summary); err != nil {
+			return err
+		}
+	} else {
+		printSummaryText(summary)
+	}
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d change(s) failed", summary.Failed)
+	}
+	return nil
+}
+
+// applyChange makes one planned change
+func applyChange(client *ssm.Client, auto *autoTagger, c planChange) error {
+	switch c.Action {
+	case "create":
+		return client.WriteSecret(c.Path, c.Value, auto.tags(c.Tags), false, cfg.KMSKey)
+	case "update":
+		if c.TagsOnly {
+			return client.SetTags(c.Path, c.Tags)
+		}
+		return client.WriteSecret(c.Path, c.Value, auto.tags(c.Tags), true, cfg.KMSKey)
+	case "delete":
+		return client.DeleteSecret(c.Path)
+	default:
+		return fmt.Errorf("unknown action %q", c.Action)
+	}
+}
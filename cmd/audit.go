@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+// nearLimitRatio is the share of the Standard-tier size limit at which a
+// value is reported as at risk
+const nearLimitRatio = 0.9
+
+var (
+	auditNearLimit bool
+	auditCerts     bool
+	auditWithin    string
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [path]",
+	Short: "Check secrets for common problems",
+	Long: `Check the secrets under a path (recursively) for common problems.
+
+Checks:
+  --near-limit   Standard-tier parameters within 10% of the 4096-byte value
+                 limit, where the next write may fail
+  --certs        PEM certificates stored in values that have expired or
+                 expire within --within (default 30d); exits 1 if any do,
+                 so it can run from cron or CI
+
+Without a path, audits all secrets you have access to. Both checks read
+(decrypt) every value under the path; values are never printed.
+
+Examples:
+  lockr audit --near-limit
+  lockr audit /myapp --near-limit --output json
+
+  # Certificates expiring in the next two weeks
+  lockr audit /myapp --certs --within 14d`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAudit,
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().BoolVar(&auditNearLimit, "near-limit", false, "report Standard-tier parameters near the 4KB size limit")
+	auditCmd.Flags().BoolVar(&auditCerts, "certs", false, "report stored PEM certificates that expire within --within")
+	auditCmd.Flags().StringVar(&auditWithin, "within", "30d", "with --certs, the expiry window (e.g. 30d, 12h)")
+}
+
+// sizeFinding is a parameter at risk of exceeding the Standard-tier limit
+type sizeFinding struct {
+	Name      string `json:"name"`
+	SizeBytes int    `json:"size_bytes"`
+	Percent   int    `json:"percent_of_limit"`
+}
+
+// certFinding is a stored certificate that has expired or expires soon
+type certFinding struct {
+	Name     string    `json:"name"`
+	Subject  string    `json:"subject"`
+	NotAfter time.Time `json:"not_after"`
+	Expired  bool      `json:"expired"`
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	if !auditNearLimit && !auditCerts {
+		return fmt.Errorf("specify a check to run (--near-limit, --certs)")
+	}
+	if cmd.Flags().Changed("within") && !auditCerts {
+		return fmt.Errorf("--within requires --certs")
+	}
+	within, err := parseDayDuration(auditWithin)
+	if err != nil {
+		return fmt.Errorf("invalid --within: %w", err)
+	}
+
+	path, err := defaultPath(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var sizeFindings []sizeFinding
+	var certFindings []certFinding
+	var unparsed []string
+	var auditErr error
+	_ = spinner.New().
+		Title("Auditing secrets...").
+		Action(func() {
+			var secrets []ssm.Secret
+			secrets, auditErr = client.ReadSecrets(path, true)
+			if auditErr != nil {
+				return
+			}
+			if auditNearLimit {
+				sizeFindings, auditErr = findNearLimit(client, path, secrets)
+				if auditErr != nil {
+					return
+				}
+			}
+			if auditCerts {
+				certFindings, unparsed = findExpiringCerts(secrets, time.Now().Add(within))
+			}
+		}).
+		Run()
+
+	if auditErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to audit secrets"))
+		return fmt.Errorf("failed to audit secrets: %w", auditErr)
+	}
+	for _, name := range unparsed {
+		fmt.Fprintln(statusOut, ui.Warningf("%s looks like a PEM certificate but couldn't be parsed", name))
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		report := map[string]interface{}{}
+		if auditNearLimit {
+			if sizeFindings == nil {
+				sizeFindings = []sizeFinding{}
+			}
+			report["near_limit"] = sizeFindings
+		}
+		if auditCerts {
+			if certFindings == nil {
+				certFindings = []certFinding{}
+			}
+			report["expiring_certs"] = certFindings
+		}
+		if err := printStructured(report); err != nil {
+			return err
+		}
+	default:
+		if auditNearLimit {
+			printNearLimit(sizeFindings, path)
+		}
+		if auditCerts {
+			printExpiringCerts(certFindings, path, auditWithin)
+		}
+	}
+
+	if len(certFindings) > 0 {
+		return silentExit(cmd, 1)
+	}
+	return nil
+}
+
+// printNearLimit prints the --near-limit findings as a table
+func printNearLimit(findings []sizeFinding, path string) {
+	fmt.Fprintln(out)
+	if len(findings) == 0 {
+		fmt.Fprintln(out, ui.Successf("No Standard-tier parameters near the %d-byte limit under %s", ssm.StandardTierMaxBytes, path))
+		fmt.Fprintln(out)
+		return
+	}
+
+	fmt.Fprintln(out, ui.SectionHeader("Near the Standard-tier size limit"))
+	fmt.Fprintln(out)
+	rows := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		rows = append(rows, []string{ui.Highlight(f.Name), fmt.Sprintf("%d", f.SizeBytes), fmt.Sprintf("%d%%", f.Percent)})
+	}
+	fmt.Fprintln(out, ui.Table([]string{"Name", "Bytes", "Of Limit"}, rows))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.Warningf("%d parameter(s) at risk - consider the Advanced tier or splitting the value", len(findings)))
+	fmt.Fprintln(out)
+}
+
+// printExpiringCerts prints the --certs findings as a table
+func printExpiringCerts(findings []certFinding, path, within string) {
+	fmt.Fprintln(out)
+	if len(findings) == 0 {
+		fmt.Fprintln(out, ui.Successf("No certificates under %s expire within %s", path, within))
+		fmt.Fprintln(out)
+		return
+	}
+
+	fmt.Fprintln(out, ui.SectionHeader("Expiring certificates"))
+	fmt.Fprintln(out)
+	now := time.Now()
+	rows := make([][]string, 0, len(findings))
+	for _, f := range findings {
+		rows = append(rows, []string{ui.Highlight(f.Name), f.Subject, f.NotAfter.Local().Format(time.RFC1123), expiresIn(now, f.NotAfter)})
+	}
+	fmt.Fprintln(out, ui.Table([]string{"Name", "Subject", "Not After", "Expires"}, rows))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.Warningf("%d certificate(s) expired or expiring within %s", len(findings), within))
+	fmt.Fprintln(out)
+}
+
+// findExpiringCerts returns the certificates in secrets' values that expire
+// before deadline, soonest first, and the names of values that contain a
+// PEM certificate that couldn't be parsed
+func findExpiringCerts(secrets []ssm.Secret, deadline time.Time) ([]certFinding, []string) {
+	now := time.Now()
+	var findings []certFinding
+	var unparsed []string
+	for _, s := range secrets {
+		if !strings.Contains(s.Value, "-----BEGIN CERTIFICATE-----") {
+			continue
+		}
+		certs, err := parseCertificates(s.Value)
+		if err != nil {
+			unparsed = append(unparsed, s.Name)
+			continue
+		}
+		for _, c := range certs {
+			if c.NotAfter.Before(deadline) {
+				findings = append(findings, certFinding{
+					Name:     s.Name,
+					Subject:  c.Subject,
+					NotAfter: c.NotAfter,
+					Expired:  c.NotAfter.Before(now),
+				})
+			}
+		}
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].NotAfter.Before(findings[j].NotAfter)
+	})
+	return findings, unparsed
+}
+
+// findNearLimit returns the Standard-tier secrets (read from path) whose
+// value is near the size limit, largest first
+func findNearLimit(client *ssm.Client, path string, secrets []ssm.Secret) ([]sizeFinding, error) {
+	// Values come from GetParametersByPath; tier only from DescribeParameters
+	metas, err := client.DescribeSecrets(path, true)
+	if err != nil {
+		return nil, err
+	}
+	tiers := make(map[string]string, len(metas))
+	for _, m := range metas {
+		tiers[m.Name] = m.Tier
+	}
+
+	var findings []sizeFinding
+	for _, s := range secrets {
+		size := len(s.Value)
+		if !nearSizeLimit(tiers[s.Name], size) {
+			continue
+		}
+		findings = append(findings, sizeFinding{
+			Name:      s.Name,
+			SizeBytes: size,
+			Percent:   size * 100 / ssm.StandardTierMaxBytes,
+		})
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].SizeBytes > findings[j].SizeBytes
+	})
+	return findings, nil
+}
+
+// nearSizeLimit reports whether a value of size bytes is close to the
+// Standard-tier limit. Other tiers have a much larger limit and are skipped.
+func nearSizeLimit(tier string, size int) bool {
+	if tier != "" && tier != "Standard" {
+		return false
+	}
+	return float64(size) >= float64(ssm.StandardTierMaxBytes)*nearLimitRatio
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+)
+
+// certExpiryWarning is how close to expiry --cert-info starts warning
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// certInfo is the --cert-info summary of one PEM certificate
+type certInfo struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	SANs      []string  `json:"sans,omitempty"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	Serial    string    `json:"serial"`
+	SHA256    string    `json:"sha256"`
+	IsCA      bool      `json:"is_ca"`
+}
+
+// parseCertificates summarizes every CERTIFICATE block in a PEM value (a leaf,
+// or a chain). Other blocks, such as a bundled private key, are skipped and
+// never shown.
+func parseCertificates(value string) ([]certInfo, error) {
+	var certs []certInfo
+	rest := []byte(value)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %w", len(certs)+1, err)
+		}
+
+		var sans []string
+		sans = append(sans, cert.DNSNames...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		sans = append(sans, cert.EmailAddresses...)
+		for _, u := range cert.URIs {
+			sans = append(sans, u.String())
+		}
+
+		sum := sha256.Sum256(cert.Raw)
+		certs = append(certs, certInfo{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			SANs:      sans,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+			Serial:    cert.SerialNumber.Text(16),
+			SHA256:    hex.EncodeToString(sum[:]),
+			IsCA:      cert.IsCA,
+		})
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("value does not contain a PEM certificate")
+	}
+	return certs, nil
+}
+
+// printCertInfo shows the subject, issuer, SANs and validity of the PEM
+// certificates in a secret instead of its value, warning about certificates
+// that have expired or expire within certExpiryWarning
+func printCertInfo(secret *ssm.Secret) error {
+	certs, err := parseCertificates(secret.Value)
+	if err != nil {
+		return fmt.Errorf("can't use --cert-info on %s: %w", secret.Name, err)
+	}
+
+	now := time.Now()
+	for i, c := range certs {
+		label := secret.Name
+		if len(certs) > 1 {
+			label = fmt.Sprintf("%s (certificate %d)", secret.Name, i+1)
+		}
+		switch {
+		case now.After(c.NotAfter):
+			fmt.Fprintln(statusOut, ui.Warningf("%s expired on %s", label, c.NotAfter.Local().Format(time.RFC1123)))
+		case c.NotAfter.Sub(now) < certExpiryWarning:
+			fmt.Fprintln(statusOut, ui.Warningf("%s expires %s", label, expiresIn(now, c.NotAfter)))
+		}
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		return printStructured(map[string]interface{}{
+			"name":         secret.Name,
+			"version":      secret.Version,
+			"certificates": certs,
+		})
+	}
+
+	for i, c := range certs {
+		title := "Certificate"
+		if len(certs) > 1 {
+			title = fmt.Sprintf("Certificate %d of %d", i+1, len(certs))
+		}
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader(title))
+		fmt.Fprintln(out)
+
+		rows := [][]string{
+			{"Subject", c.Subject},
+			{"Issuer", c.Issuer},
+			{"SANs", strings.Join(c.SANs, ", ")},
+			{"Not Before", c.NotBefore.Local().Format(time.RFC1123)},
+			{"Not After", fmt.Sprintf("%s (%s)", c.NotAfter.Local().Format(time.RFC1123), expiresIn(now, c.NotAfter))},
+			{"Serial", c.Serial},
+			{"SHA-256", c.SHA256},
+		}
+		if c.IsCA {
+			rows = append(rows, []string{"CA", "yes"})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Property", "Value"}, rows))
+	}
+	fmt.Fprintln(out)
+	return nil
+}
+
+// expiresIn describes the time until notAfter in days (or hours, when less
+// than a day is left)
+func expiresIn(now, notAfter time.Time) string {
+	d := notAfter.Sub(now)
+	if d < 0 {
+		return "expired " + timeAgo(notAfter)
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("in %d hours", int(d.Hours()))
+	}
+	return fmt.Sprintf("in %d days", int(d.Hours()/24))
+}
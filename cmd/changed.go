@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var changedSinceLabel string
+
+// changedWorkers bounds concurrent GetParameterHistory calls
+const changedWorkers = 8
+
+var changedCmd = &cobra.Command{
+	Use:   "changed <path> --since-label <label>",
+	Short: "Find secrets changed since a labeled version",
+	Long: `Find the secrets under a path (recursively) that have new versions since
+the version carrying a label, e.g. a "deployed" label moved to the current
+version on every deploy with aws ssm label-parameter-version.
+
+Secrets without the label are not compared; their count is shown so a
+missing label isn't mistaken for "unchanged". Each secret's history is read
+(one GetParameterHistory call per secret); values are not decrypted.
+
+Examples:
+  # What changed since the last deploy?
+  lockr changed /myapp/prod --since-label deployed
+
+  lockr changed /myapp --since-label deployed --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChanged,
+}
+
+func init() {
+	rootCmd.AddCommand(changedCmd)
+
+	changedCmd.Flags().StringVar(&changedSinceLabel, "since-label", "", "label marking the baseline version (required)")
+	_ = changedCmd.MarkFlagRequired("since-label")
+}
+
+// changedSecret is a secret with versions newer than the labeled one
+type changedSecret struct {
+	Name             string     `json:"name"`
+	LabeledVersion   int64      `json:"labeled_version"`
+	CurrentVersion   int64      `json:"current_version"`
+	LastModified     *time.Time `json:"last_modified,omitempty"`
+	LastModifiedUser string     `json:"last_modified_user,omitempty"`
+}
+
+func runChanged(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var changed []changedSecret
+	var unlabeled []string
+	var findErr error
+	_ = spinner.New().
+		Title("Comparing versions...").
+		Action(func() {
+			changed, unlabeled, findErr = findChangedSince(client, path, changedSinceLabel)
+		}).
+		Run()
+
+	if findErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to compare versions"))
+		return fmt.Errorf("failed to compare versions: %w", findErr)
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if changed == nil {
+			changed = []changedSecret{}
+		}
+		if unlabeled == nil {
+			unlabeled = []string{}
+		}
+		return printStructured(map[string]interface{}{"changed": changed, "unlabeled": unlabeled})
+	}
+
+	fmt.Fprintln(out)
+	if len(changed) == 0 {
+		fmt.Fprintln(out, ui.Successf("No secrets under %s changed since the %q version", path, changedSinceLabel))
+	} else {
+		rows := make([][]string, 0, len(changed))
+		for _, c := range changed {
+			modified := ""
+			if c.LastModified != nil {
+				modified = timeAgo(*c.LastModified)
+			}
+			rows = append(rows, []string{
+				ui.Highlight(c.Name),
+				fmt.Sprintf("v%d", c.LabeledVersion),
+				fmt.Sprintf("v%d", c.CurrentVersion),
+				modified,
+				c.LastModifiedUser,
+			})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Name", changedSinceLabel, "Current", "Modified", "By"}, rows))
+	}
+	fmt.Fprintln(out)
+
+	if len(unlabeled) > 0 {
+		fmt.Fprintln(statusOut, ui.Subtle(fmt.Sprintf("%d secret(s) have no %q label and were not compared", len(unlabeled), changedSinceLabel)))
+		fmt.Fprintln(statusOut)
+	}
+	return nil
+}
+
+// findChangedSince returns the secrets under path whose latest version is
+// newer than the version carrying label, sorted by name, and the names of
+// secrets without the label
+func findChangedSince(client *ssm.Client, path, label string) ([]changedSecret, []string, error) {
+	secrets, err := client.ListSecrets(path, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]*changedSecret, len(secrets))
+	labeled := make([]bool, len(secrets))
+	errs := make([]error, len(secrets))
+	sem := make(chan struct{}, changedWorkers)
+	var wg sync.WaitGroup
+	for i := range secrets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			versions, err := client.History(secrets[i].Name, false)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", secrets[i].Name, err)
+				return
+			}
+			results[i], labeled[i] = changedSince(secrets[i].Name, versions, label)
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	var changed []changedSecret
+	var unlabeled []string
+	for i, s := range secrets {
+		switch {
+		case !labeled[i]:
+			unlabeled = append(unlabeled, s.Name)
+		case results[i] != nil:
+			changed = append(changed, *results[i])
+		}
+	}
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	sort.Strings(unlabeled)
+	return changed, unlabeled, nil
+}
+
+// changedSince compares the newest version against the one carrying label.
+// It reports whether the label was found, and the change if the newest
+// version is later.
+func changedSince(name string, versions []ssm.SecretVersion, label string) (*changedSecret, bool) {
+	var labeledVersion int64
+	var latest *ssm.SecretVersion
+	for i, v := range versions {
+		for _, l := range v.Labels {
+			if l == label {
+				labeledVersion = v.Version
+			}
+		}
+		if latest == nil || v.Version > latest.Version {
+			latest = &versions[i]
+		}
+	}
+
+	if labeledVersion == 0 {
+		return nil, false
+	}
+	if latest.Version <= labeledVersion {
+		return nil, true
+	}
+	return &changedSecret{
+		Name:             name,
+		LabeledVersion:   labeledVersion,
+		CurrentVersion:   latest.Version,
+		LastModified:     latest.LastModified,
+		LastModifiedUser: latest.LastModifiedUser,
+	}, true
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errNoClipboard is returned when no clipboard tool can be found, e.g. on a
+// headless server or in CI
+var errNoClipboard = errors.New("no clipboard available (needs pbcopy on macOS, clip on Windows, or wl-copy, xclip or xsel with a display on Linux)")
+
+// clipboardCommands returns the commands that write and read the system
+// clipboard on this platform, or errNoClipboard
+func clipboardCommands() (copyCmd, pasteCmd []string, err error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"pbcopy"}, []string{"pbpaste"}, nil
+	case "windows":
+		return []string{"clip"}, []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"}, nil
+	}
+
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if _, err := exec.LookPath("wl-copy"); err == nil {
+			return []string{"wl-copy"}, []string{"wl-paste", "--no-newline"}, nil
+		}
+	}
+	if os.Getenv("DISPLAY") != "" {
+		if _, err := exec.LookPath("xclip"); err == nil {
+			return []string{"xclip", "-selection", "clipboard"}, []string{"xclip", "-selection", "clipboard", "-o"}, nil
+		}
+		if _, err := exec.LookPath("xsel"); err == nil {
+			return []string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}, nil
+		}
+	}
+	return nil, nil, errNoClipboard
+}
+
+// copyToClipboard puts value on the system clipboard
+func copyToClipboard(value string) error {
+	copyCmd, _, err := clipboardCommands()
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(copyCmd[0]); err != nil {
+		return errNoClipboard
+	}
+
+	c := exec.Command(copyCmd[0], copyCmd[1:]...)
+	c.Stdin = strings.NewReader(value)
+	return c.Run()
+}
+
+// clearClipboard empties the clipboard if it still holds value, so something
+// the user copied in the meantime isn't wiped. If the clipboard can't be
+// read back it is cleared anyway.
+func clearClipboard(value string) error {
+	_, pasteCmd, err := clipboardCommands()
+	if err != nil {
+		return err
+	}
+
+	current, err := exec.Command(pasteCmd[0], pasteCmd[1:]...).Output()
+	if err == nil && strings.TrimRight(string(current), "\r\n") != strings.TrimRight(value, "\r\n") {
+		return nil
+	}
+	return copyToClipboard("")
+}
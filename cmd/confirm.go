@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/devops-chris/clihq/ui"
+)
+
+// skipConfirm reports whether a confirmation prompt should be skipped: when
+// the command's own --force (or --auto-approve) is set, or when assume_yes
+// (LOCKR_ASSUME_YES) says to skip every prompt
+func skipConfirm(force bool) bool {
+	return force || cfg.AssumeYes
+}
+
+// confirmTyped asks the user to type a word before a destructive action, so
+// it can't be confirmed by reflex. The word is confirm_word when set, and
+// otherwise expected (the path, or the number of secrets). action completes
+// the prompt, e.g. "delete this secret". The answer is read from in, or from
+// stdin if in is nil.
+func confirmTyped(action, expected string, in io.Reader) (bool, error) {
+	word := expected
+	if cfg.ConfirmWord != "" {
+		word = cfg.ConfirmWord
+	}
+
+	var typed string
+	form := huh.NewForm(huh.NewGroup(
+		huh.NewInput().
+			Title(fmt.Sprintf("Type %s to %s", word, action)).
+			Value(&typed),
+	)).WithTheme(ui.Theme())
+	if in != nil {
+		form = form.WithInput(in)
+	}
+	if err := form.Run(); err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(typed) == word, nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	copyWithPolicies bool
+	copyMetadataOnly bool
+	copyPlaceholder  string
+	copyOverwrite    bool
+)
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <source> <destination>",
+	Short: "Copy a secret to another path",
+	Long: `Copy a secret to another path, keeping the source.
+
+The destination gets the source's value, type, tier, description, KMS key
+and tags. Parameter policies are only copied with --with-policies, since an
+Expiration policy would delete the copy at the same time as the source.
+
+Version history is NOT copied: SSM can't set version numbers, so the
+destination starts at version 1 (see move --carry-history to replay versions).
+
+With --metadata-only, everything except the value is copied and the
+destination holds --placeholder instead, to set up a secret with the right
+shape before its real value is known.
+
+Examples:
+  lockr copy /myapp/prod/api-key /myapp/staging/api-key
+
+  # Include the Expiration/notification policies
+  lockr copy /myapp/prod/api-key /myapp/staging/api-key --with-policies
+
+  # Same description, tier, key and tags, but a placeholder value
+  lockr copy /myapp/prod/db-password /newapp/prod/db-password --metadata-only
+  lockr write /newapp/prod/db-password`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCopy,
+}
+
+func init() {
+	rootCmd.AddCommand(copyCmd)
+
+	copyCmd.Flags().BoolVar(&copyWithPolicies, "with-policies", false, "also copy parameter policies (Expiration, notifications)")
+	copyCmd.Flags().BoolVar(&copyMetadataOnly, "metadata-only", false, "copy everything except the value, writing --placeholder instead")
+	copyCmd.Flags().StringVar(&copyPlaceholder, "placeholder", "placeholder", "value written by --metadata-only")
+	copyCmd.Flags().BoolVar(&copyOverwrite, "overwrite", false, "replace the destination if it exists")
+}
+
+func runCopy(cmd *cobra.Command, args []string) error {
+	src, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	dst, err := buildPath(args[1])
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("source and destination are the same")
+	}
+	if cmd.Flags().Changed("placeholder") && !copyMetadataOnly {
+		return fmt.Errorf("--placeholder requires --metadata-only")
+	}
+	if copyMetadataOnly && copyPlaceholder == "" {
+		return fmt.Errorf("--placeholder must not be empty: SSM rejects empty values")
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	opts := ssm.CopyOptions{Policies: copyWithPolicies, Overwrite: copyOverwrite}
+	if copyMetadataOnly {
+		opts.Placeholder = copyPlaceholder
+	}
+
+	var copyErr error
+	_ = spinner.New().
+		Title("Copying secret...").
+		Action(func() {
+			copyErr = client.CopySecret(src, dst, opts)
+		}).
+		Run()
+	emitOutcome(client, "copy", copyErr)
+
+	if copyErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to copy secret"))
+		if ssm.IsAlreadyExists(copyErr) {
+			return fmt.Errorf("destination %s already exists (use --overwrite to replace it)", dst)
+		}
+		return fmt.Errorf("failed to copy secret: %w", copyErr)
+	}
+
+	if cfg.Output != "text" {
+		return printStructured(writeResult{Path: dst, Status: "copied"})
+	}
+	if copyMetadataOnly {
+		fmt.Println(ui.Successf("Copied metadata %s → %s (value is a placeholder)", src, dst))
+	} else {
+		fmt.Println(ui.Successf("Copied %s → %s", src, dst))
+	}
+	return nil
+}
@@ -1,32 +1,77 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
-	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/devops-chris/clihq/ui"
 	"github.com/devops-chris/lockr/internal/ssm"
 	"github.com/spf13/cobra"
 )
 
-var deleteForce bool
+var (
+	deleteForce     bool
+	deleteRecursive bool
+	deleteDryRun    bool
+	deleteStdin     bool
+)
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete <path>",
+	Use:   "delete <path>... | --stdin",
 	Short: "Delete a secret from SSM Parameter Store",
-	Long: `Delete a secret from AWS SSM Parameter Store.
+	Long: `Delete one or more secrets from AWS SSM Parameter Store.
+
+By default, you'll be prompted to confirm deletion by typing the path (or
+the confirm_word setting). Use --force to skip confirmation;
+LOCKR_ASSUME_YES=1 skips it for every command (dangerous - automation only).
+
+With --recursive, every secret under each path is deleted. The full list is
+shown first and you must type the number of secrets (or confirm_word) to
+confirm. Use --dry-run
+to only print what would be deleted.
 
-By default, you'll be prompted to confirm deletion.
-Use --force to skip confirmation.
+With --stdin, the paths are read from stdin, one per line, so the output of
+lockr list --names-only can be filtered with grep or awk and piped in. The
+list is shown and you must type the number of secrets to confirm (on the
+terminal, since stdin is the pipe); --force skips that. Several secrets are
+deleted with batched DeleteParameters calls.
+
+Deleting a secret also deletes its whole history and version labels, which
+other systems may reference (e.g. name:production). Before asking, and in
+--dry-run, the secrets' labels are looked up and shown so they aren't lost by
+accident (one GetParameterHistory call per secret; skipped with --force).
+
+With --output json, prints the deleted and failed paths, e.g.
+  {"deleted": ["/myapp/prod/old-key"], "failed": [], "status": "ok"}
 
 Examples:
   # Delete with confirmation
   lockr delete /myapp/prod/old-key
 
   # Delete without confirmation
-  lockr delete /myapp/prod/old-key --force`,
-	Args: cobra.ExactArgs(1),
+  lockr delete /myapp/prod/old-key --force
+
+  # Preview a recursive delete (nothing is deleted)
+  lockr delete /myapp/old --recursive --dry-run
+
+  # Delete everything under a path (typed confirmation)
+  lockr delete /myapp/old --recursive
+
+  # Delete whatever a filtered listing prints
+  lockr list /myapp/old --recursive --names-only | grep -v keep | lockr delete --stdin --force
+
+  # Delete several secrets, machine-readable result
+  lockr delete /myapp/prod/a /myapp/prod/b --force --output json`,
+	Args: cobra.ArbitraryArgs,
 	RunE: runDelete,
 }
 
@@ -34,53 +79,319 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "skip confirmation prompt")
+	deleteCmd.Flags().BoolVarP(&deleteRecursive, "recursive", "r", false, "delete every secret under the given paths")
+	deleteCmd.Flags().BoolVar(&deleteStdin, "stdin", false, "read the paths to delete from stdin, one per line")
+	deleteCmd.Flags().BoolVar(&deleteDryRun, "dry-run", false, "print what would be deleted without deleting anything")
+}
+
+// deleteResult is the outcome of a delete, as printed by --output json
+type deleteResult struct {
+	Deleted []string        `json:"deleted"`
+	Failed  []deleteFailure `json:"failed"`
+	Status  string          `json:"status"` // ok, partial, failed
+
+	// Summary totals the outcome when more than one secret was deleted
+	Summary *batchSummary `json:"summary,omitempty"`
+}
+
+type deleteFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	path := buildPath(args[0])
+	switch {
+	case deleteStdin && len(args) > 0:
+		return fmt.Errorf("--stdin cannot be combined with path arguments")
+	case !deleteStdin && len(args) == 0:
+		return fmt.Errorf("requires at least one path (or --stdin)")
+	}
 
-	// Confirm deletion unless --force
-	if !deleteForce {
-		fmt.Println()
-		fmt.Println(ui.Warningf("You are about to delete: %s", ui.Error(path)))
-		fmt.Println()
+	if deleteStdin {
+		var err error
+		args, err = readPathLines(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read paths from stdin: %w", err)
+		}
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, ui.Warning("No paths on stdin, nothing to delete"))
+			return nil
+		}
+	}
 
-		var confirmed bool
-		confirm := huh.NewConfirm().
-			Title("Are you sure you want to delete this secret?").
-			Value(&confirmed)
-		confirm.WithTheme(ui.Theme())
-		if err := confirm.Run(); err != nil {
+	paths := make([]string, len(args))
+	for i, arg := range args {
+		var err error
+		if paths[i], err = buildPath(arg); err != nil {
 			return err
 		}
+	}
 
-		if !confirmed {
-			fmt.Println(ui.Info("Cancelled"))
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	if deleteRecursive {
+		var listErr error
+		roots := paths
+		_ = spinner.New().
+			Title("Finding secrets...").
+			Action(func() {
+				paths, listErr = secretsUnder(client, roots)
+			}).
+			Run()
+		if listErr != nil {
+			fmt.Println(ui.Error("Failed to list secrets"))
+			return fmt.Errorf("failed to list secrets: %w", listErr)
+		}
+		if len(paths) == 0 {
+			fmt.Fprintln(statusOut, ui.Warningf("No secrets found under %s", strings.Join(roots, ", ")))
 			return nil
 		}
 	}
 
-	client, err := ssm.NewClient(cfg.Region)
-	if err != nil {
-		return fmt.Errorf("failed to create SSM client: %w", err)
+	var labels map[string][]string
+	if deleteDryRun || !skipConfirm(deleteForce) {
+		_ = spinner.New().
+			Title("Checking version labels...").
+			Action(func() {
+				labels, err = versionLabels(client, paths)
+			}).
+			Run()
+		if err != nil {
+			fmt.Fprintln(statusOut, ui.Warningf("Couldn't check version labels: %v", err))
+		}
+	}
+
+	if deleteDryRun {
+		return printDeletePreview(out, paths, labels)
+	}
+
+	if err := confirmRegion(client); err != nil {
+		return err
 	}
 
-	var deleteErr error
+	// Confirm deletion unless --force (or assume_yes)
+	if !skipConfirm(deleteForce) {
+		// With --stdin the prompt has to read from the terminal instead
+		var input io.Reader
+		if deleteStdin {
+			tty, err := openTTY()
+			if err != nil {
+				return fmt.Errorf("no terminal to confirm on; pass --force to delete paths read from stdin")
+			}
+			defer tty.Close()
+			input = tty
+		}
+
+		if deleteRecursive || deleteStdin {
+			_ = printDeletePreview(statusOut, paths, labels)
+		} else {
+			fmt.Fprintln(statusOut)
+			for _, path := range paths {
+				fmt.Fprintln(statusOut, ui.Warningf("You are about to delete: %s", ui.Error(path)))
+				if l := labels[path]; len(l) > 0 {
+					fmt.Fprintln(statusOut, ui.Warningf("  this has labels: %s - deleting loses them", strings.Join(l, ", ")))
+				}
+			}
+			fmt.Fprintln(statusOut)
+		}
+
+		// A single secret is confirmed by its path, several by their count
+		expected, action := paths[0], "delete this secret"
+		if len(paths) > 1 {
+			expected = strconv.Itoa(len(paths))
+			action = fmt.Sprintf("delete these %d secrets", len(paths))
+		}
+		confirmed, err := confirmTyped(action, expected, input)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Fprintln(statusOut, ui.Info("Cancelled"))
+			return nil
+		}
+	}
+
+	result := deleteResult{Deleted: []string{}, Failed: []deleteFailure{}}
 	_ = spinner.New().
 		Title("Deleting secret...").
 		Action(func() {
-			deleteErr = client.DeleteSecret(path)
+			if len(paths) == 1 {
+				if err := client.DeleteSecret(paths[0]); err != nil {
+					result.Failed = append(result.Failed, deleteFailure{Path: paths[0], Error: err.Error()})
+				} else {
+					result.Deleted = append(result.Deleted, paths[0])
+				}
+				return
+			}
+
+			deleted, failed := client.DeleteSecrets(paths)
+			result.Deleted = append(result.Deleted, deleted...)
+			sort.Strings(result.Deleted)
+			for _, path := range paths {
+				if err, ok := failed[path]; ok {
+					result.Failed = append(result.Failed, deleteFailure{Path: path, Error: err.Error()})
+				}
+			}
 		}).
 		Run()
+	emitMetrics(client, "delete", len(result.Deleted), len(result.Failed))
+
+	switch {
+	case len(result.Failed) == 0:
+		result.Status = "ok"
+	case len(result.Deleted) == 0:
+		result.Status = "failed"
+	default:
+		result.Status = "partial"
+	}
+	if len(paths) > 1 {
+		summary := batchSummary{Deleted: len(result.Deleted), Failed: len(result.Failed), Failures: []batchFailure{}}
+		for _, f := range result.Failed {
+			summary.Failures = append(summary.Failures, batchFailure{Path: f.Path, Action: "delete", Error: f.Error})
+		}
+		result.Summary = &summary
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if err := printStructured(result); err != nil {
+			return err
+		}
+	default:
+		fmt.Println()
+		for _, path := range result.Deleted {
+			fmt.Println(ui.Successf("Deleted: %s", path))
+		}
+		for _, f := range result.Failed {
+			fmt.Println(ui.Errorf("Failed to delete %s: %s", f.Path, f.Error))
+		}
+		fmt.Println()
+		if result.Summary != nil {
+			printSummaryText(*result.Summary)
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		if len(paths) == 1 {
+			return fmt.Errorf("failed to delete secret: %s", result.Failed[0].Error)
+		}
+		return fmt.Errorf("failed to delete %d of %d secrets", len(result.Failed), len(paths))
+	}
+
+	return nil
+}
 
-	if deleteErr != nil {
-		fmt.Println(ui.Error("Failed to delete secret"))
-		return fmt.Errorf("failed to delete secret: %w", deleteErr)
+// secretsUnder returns the names of every secret under the given paths,
+// sorted and without duplicates
+func secretsUnder(client *ssm.Client, roots []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, root := range roots {
+		secrets, err := client.ListSecrets(root, true)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", root, err)
+		}
+		for _, s := range secrets {
+			if !seen[s.Name] {
+				seen[s.Name] = true
+				names = append(names, s.Name)
+			}
+		}
 	}
+	sort.Strings(names)
+	return names, nil
+}
 
-	fmt.Println()
-	fmt.Println(ui.Successf("Deleted: %s", path))
-	fmt.Println()
+// printDeletePreview lists the paths a delete would remove, with their
+// version labels, and their count, to w: out for the --dry-run result, or
+// statusOut when it's shown before asking to confirm
+func printDeletePreview(w io.Writer, paths []string, labels map[string][]string) error {
+	if cfg.Output != "text" && deleteDryRun {
+		preview := map[string]interface{}{"would_delete": paths, "count": len(paths)}
+		if len(labels) > 0 {
+			preview["labels"] = labels
+		}
+		return printStructured(preview)
+	}
 
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.SectionHeader("Would delete"))
+	fmt.Fprintln(w)
+	for _, path := range paths {
+		line := "  " + ui.Error(path)
+		if l := labels[path]; len(l) > 0 {
+			line += "  " + ui.Subtle("labels: "+strings.Join(l, ", "))
+		}
+		fmt.Fprintln(w, line)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, ui.Warningf("Total: %d secret(s)", len(paths)))
+	if len(labels) > 0 {
+		fmt.Fprintln(w, ui.Warningf("%d of them have version labels - deleting loses them", len(labels)))
+	}
+	fmt.Fprintln(w)
 	return nil
 }
+
+// labelWorkers bounds concurrent GetParameterHistory calls for versionLabels
+const labelWorkers = 8
+
+// versionLabels returns the labels on any version of each path, sorted, for
+// the paths that have labels. Paths that no longer exist are skipped.
+func versionLabels(client *ssm.Client, paths []string) (map[string][]string, error) {
+	found := make([][]string, len(paths))
+	errs := make([]error, len(paths))
+	sem := make(chan struct{}, labelWorkers)
+	var wg sync.WaitGroup
+	for i := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			versions, err := client.History(paths[i], false)
+			if err != nil {
+				if !ssm.IsNotFound(err) {
+					errs[i] = fmt.Errorf("%s: %w", paths[i], err)
+				}
+				return
+			}
+			for _, v := range versions {
+				found[i] = append(found[i], v.Labels...)
+			}
+			sort.Strings(found[i])
+		}(i)
+	}
+	wg.Wait()
+
+	labels := make(map[string][]string)
+	for i, path := range paths {
+		if len(found[i]) > 0 {
+			labels[path] = found[i]
+		}
+	}
+	return labels, errors.Join(errs...)
+}
+
+// readPathLines reads newline-separated paths, skipping blank lines
+func readPathLines(r io.Reader) ([]string, error) {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, scanner.Err()
+}
+
+// openTTY opens the controlling terminal, for prompting while stdin is a pipe
+func openTTY() (*os.File, error) {
+	if runtime.GOOS == "windows" {
+		return os.Open("CONIN$")
+	}
+	return os.Open("/dev/tty")
+}
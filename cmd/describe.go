@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <path>",
+	Short: "Show metadata for a secret",
+	Long: `Show metadata for a secret without printing its value.
+
+Includes the tier, last modified user, value size and any parameter
+policies (see also lockr policies). Standard-tier
+parameters within 10% of the 4096-byte limit are flagged, since the next
+write may fail.
+
+Examples:
+  lockr describe /myapp/prod/api-key
+  lockr describe /myapp/prod/api-key --output json
+  lockr describe /myapp/prod/api-key --output tsv`,
+	Args:        cobra.ExactArgs(1),
+	RunE:        runDescribe,
+	Annotations: map[string]string{tsvAnnotation: "true"},
+}
+
+func init() {
+	rootCmd.AddCommand(describeCmd)
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var meta *ssm.SecretMetadata
+	var secret *ssm.Secret
+	var descErr error
+	_ = spinner.New().
+		Title("Describing secret...").
+		Action(func() {
+			meta, descErr = client.DescribeSecret(path)
+			if descErr != nil {
+				return
+			}
+			// The value is only read to measure its size
+			secret, descErr = client.ReadSecret(path)
+		}).
+		Run()
+
+	if descErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to describe secret"))
+		return fmt.Errorf("failed to describe secret: %w", descErr)
+	}
+
+	secret.Tags = redactTags(secret.Tags)
+	size := len(secret.Value)
+	near := nearSizeLimit(meta.Tier, size)
+
+	switch cfg.Output {
+	case "json", "yaml":
+		output := map[string]interface{}{
+			"name":       meta.Name,
+			"type":       meta.Type,
+			"tier":       meta.Tier,
+			"version":    meta.Version,
+			"size_bytes": size,
+			"near_limit": near,
+		}
+		if meta.LastModified != nil {
+			output["last_modified"] = meta.LastModified
+		}
+		if meta.LastModifiedUser != "" {
+			output["last_modified_user"] = meta.LastModifiedUser
+		}
+		if meta.Description != "" {
+			output["description"] = meta.Description
+		}
+		if meta.KeyID != "" {
+			output["kms_key_id"] = meta.KeyID
+		}
+		if len(meta.Policies) > 0 {
+			output["policies"] = meta.Policies
+		}
+		if len(secret.Tags) > 0 {
+			output["tags"] = secret.Tags
+		}
+		if err := printStructured(output); err != nil {
+			return err
+		}
+	case "tsv":
+		lastMod := ""
+		if meta.LastModified != nil {
+			lastMod = meta.LastModified.UTC().Format(time.RFC3339)
+		}
+		printTSV(
+			[]string{"name", "type", "tier", "version", "size_bytes", "near_limit", "last_modified", "last_modified_user", "description", "kms_key_id"},
+			[][]string{{meta.Name, meta.Type, meta.Tier, fmt.Sprintf("%d", meta.Version), fmt.Sprintf("%d", size), fmt.Sprintf("%t", near), lastMod, meta.LastModifiedUser, meta.Description, meta.KeyID}},
+		)
+	default:
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader("Secret"))
+		fmt.Fprintln(out)
+
+		rows := [][]string{
+			{"Name", meta.Name},
+			{"Type", meta.Type},
+			{"Tier", meta.Tier},
+			{"Version", fmt.Sprintf("%d", meta.Version)},
+			{"Size", formatSize(meta.Tier, size)},
+		}
+		if meta.LastModified != nil {
+			rows = append(rows, []string{"Modified", meta.LastModified.Local().Format("2006-01-02 15:04:05")})
+		}
+		if meta.LastModifiedUser != "" {
+			rows = append(rows, []string{"Modified By", meta.LastModifiedUser})
+		}
+		if meta.Description != "" {
+			rows = append(rows, []string{"Description", meta.Description})
+		}
+		if meta.KeyID != "" {
+			rows = append(rows, []string{"KMS Key", meta.KeyID})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Property", "Value"}, rows))
+
+		if len(meta.Policies) > 0 {
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.SectionHeader("Policies"))
+			fmt.Fprintln(out)
+			policyRows := make([][]string, len(meta.Policies))
+			for i, p := range meta.Policies {
+				policyRows[i] = []string{p.Type, p.Status, policyDetails(p)}
+			}
+			fmt.Fprintln(out, ui.Table([]string{"Policy", "Status", "Parameters"}, policyRows))
+		}
+
+		if len(secret.Tags) > 0 {
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.SectionHeader("Tags"))
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.Table([]string{"Key", "Value"}, sortedKeyValueRows(secret.Tags)))
+		}
+		fmt.Fprintln(out)
+
+		if near {
+			fmt.Fprintln(statusOut, ui.Warningf("Value is within 10%% of the %d-byte Standard-tier limit", ssm.StandardTierMaxBytes))
+			fmt.Fprintln(statusOut)
+		}
+	}
+
+	return nil
+}
+
+// formatSize renders a value size, with the share of the limit for
+// Standard-tier parameters
+func formatSize(tier string, size int) string {
+	if tier != "" && tier != "Standard" {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	return fmt.Sprintf("%d bytes (%d%% of %d)", size, size*100/ssm.StandardTierMaxBytes, ssm.StandardTierMaxBytes)
+}
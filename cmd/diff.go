@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCompareRegion string
+	diffValues        bool
+	diffReveal        bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <path> [other-path]",
+	Short: "Compare secrets between two paths or regions",
+	Long: `Compare the secrets under two paths, or the same path in two regions.
+
+Keys are compared relative to each path, recursively. By default only the
+presence of keys is compared; use --values to also compare values. Values are
+compared by SHA-256 hash and only short fingerprints are shown, so checking
+that two environments are in sync never prints a secret; add --reveal to show
+the differing values themselves.
+
+Examples:
+  # Compare two environments
+  lockr diff /myapp/staging /myapp/prod
+
+  # Compare the same path across regions (e.g. to verify replication)
+  lockr diff /myapp/prod --compare-region us-west-2
+
+  # Also compare values (shows fingerprints, never values)
+  lockr diff /myapp/prod --compare-region us-west-2 --values
+
+  # Show the actual differing values
+  lockr diff /myapp/staging /myapp/prod --values --reveal`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffCompareRegion, "compare-region", "", "compare against the same path in another region")
+	diffCmd.Flags().BoolVar(&diffValues, "values", false, "compare values by hash, not just keys")
+	diffCmd.Flags().BoolVar(&diffReveal, "reveal", false, "with --values, show differing values instead of fingerprints")
+}
+
+// diffEntry is one key that differs between the two sides of a diff
+type diffEntry struct {
+	Key    string `json:"key"`
+	Status string `json:"status"` // only_left, only_right, changed
+	Left   string `json:"left,omitempty"`
+	Right  string `json:"right,omitempty"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 && diffCompareRegion == "" {
+		return fmt.Errorf("specify a second path or --compare-region")
+	}
+	if diffReveal && !diffValues {
+		return fmt.Errorf("--reveal requires --values")
+	}
+
+	leftPath, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	rightPath := leftPath
+	if len(args) == 2 {
+		if rightPath, err = buildPath(args[1]); err != nil {
+			return err
+		}
+	}
+
+	leftClient, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	rightClient := leftClient
+	leftLabel, rightLabel := leftPath, rightPath
+	if diffCompareRegion != "" {
+		rightClient, err = newClient(diffCompareRegion)
+		if err != nil {
+			return fmt.Errorf("failed to create SSM client for %s: %w", diffCompareRegion, err)
+		}
+		leftRegion := cfg.Region
+		if leftRegion == "" {
+			leftRegion = "default region"
+		}
+		leftLabel = fmt.Sprintf("%s (%s)", leftPath, leftRegion)
+		rightLabel = fmt.Sprintf("%s (%s)", rightPath, diffCompareRegion)
+	}
+
+	var left, right map[string]string
+	var fetchErr error
+	_ = spinner.New().
+		Title("Fetching secrets...").
+		Action(func() {
+			left, fetchErr = fetchDiffSide(leftClient, leftPath)
+			if fetchErr != nil {
+				return
+			}
+			right, fetchErr = fetchDiffSide(rightClient, rightPath)
+		}).
+		Run()
+
+	if fetchErr != nil {
+		fmt.Println(ui.Error("Failed to fetch secrets"))
+		return fmt.Errorf("failed to fetch secrets: %w", fetchErr)
+	}
+
+	entries := diffSides(left, right)
+
+	switch cfg.Output {
+	case "json", "yaml":
+		output := map[string]interface{}{
+			"left":        leftLabel,
+			"right":       rightLabel,
+			"differences": entries,
+		}
+		if err := printStructured(output); err != nil {
+			return err
+		}
+	default:
+		fmt.Println()
+		if len(entries) == 0 {
+			fmt.Println(ui.Successf("No differences between %s and %s", leftLabel, rightLabel))
+			fmt.Println()
+			return nil
+		}
+
+		fmt.Println(ui.SectionHeader(fmt.Sprintf("%s vs %s", leftLabel, rightLabel)))
+		fmt.Println()
+
+		headers := []string{"Key", "Status"}
+		if diffValues {
+			headers = append(headers, leftLabel, rightLabel)
+		}
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			var status string
+			switch e.Status {
+			case "only_left":
+				status = "only in " + leftLabel
+			case "only_right":
+				status = "only in " + rightLabel
+			default:
+				status = "value differs"
+			}
+			row := []string{ui.Highlight(e.Key), status}
+			if diffValues {
+				row = append(row, e.Left, e.Right)
+			}
+			rows = append(rows, row)
+		}
+		fmt.Println(ui.Table(headers, rows))
+		fmt.Println()
+		fmt.Println(ui.Warningf("%d difference(s)", len(entries)))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// fetchDiffSide returns the secrets under path keyed by relative name. Values
+// are only populated when --values is set, and then as fingerprints unless
+// --reveal is set.
+func fetchDiffSide(client *ssm.Client, path string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	if diffValues {
+		secrets, err := client.ReadSecrets(path, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range secrets {
+			value := s.Value
+			if !diffReveal {
+				value = fingerprint(value)
+			}
+			result[relativeName(s.Name, path)] = value
+		}
+		return result, nil
+	}
+
+	secrets, err := client.ListSecrets(path, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range secrets {
+		result[relativeName(s.Name, path)] = ""
+	}
+	return result, nil
+}
+
+// diffSides compares two key/value maps and returns the differing keys in
+// sorted order
+func diffSides(left, right map[string]string) []diffEntry {
+	keys := make([]string, 0, len(left)+len(right))
+	for k := range left {
+		keys = append(keys, k)
+	}
+	for k := range right {
+		if _, ok := left[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	entries := []diffEntry{}
+	for _, k := range keys {
+		lv, inLeft := left[k]
+		rv, inRight := right[k]
+		switch {
+		case !inRight:
+			entries = append(entries, diffEntry{Key: k, Status: "only_left", Left: lv})
+		case !inLeft:
+			entries = append(entries, diffEntry{Key: k, Status: "only_right", Right: rv})
+		case lv != rv:
+			entries = append(entries, diffEntry{Key: k, Status: "changed", Left: lv, Right: rv})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var execEnvMap []string
+
+var execCmd = &cobra.Command{
+	Use:   "exec <path> -- <command> [args...]",
+	Short: "Run a command with secrets as environment variables",
+	Long: `Run a command with every secret under a path (recursively) added to its
+environment. Secrets are never written to disk.
+
+Variable names are derived the same way as 'lockr export': the relative path
+upper-cased with / . and - replaced by _ (db/password -> DB_PASSWORD).
+Secrets override variables of the same name already in the environment.
+
+--env-map sets specific variables from specific parameters (NAME=path, comma-
+separated or repeated) for apps that expect particular names. Mapped paths
+may be anywhere, relative paths use prefix/env, and mapped variables win over
+the ones derived from <path>.
+
+The command's exit code is passed through.
+
+Examples:
+  lockr exec /myapp/prod -- ./server
+
+  # Also set DATABASE_URL from a parameter outside the path
+  lockr exec /myapp/prod --env-map DATABASE_URL=/shared/prod/db-url -- ./server
+
+  # Split StringList values into HOSTS_0, HOSTS_1, ...
+  lockr exec /myapp/prod --expand-lists -- env`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runExec,
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+
+	execCmd.Flags().BoolVar(&expandLists, "expand-lists", false, "set StringList elements as numbered variables (KEY_0, KEY_1, ...)")
+	execCmd.Flags().StringSliceVar(&execEnvMap, "env-map", nil, "set NAME from the parameter at path (NAME=path, comma-separated or repeated)")
+	execCmd.Flags().BoolVar(&escapeNewlines, "escape-newlines", false, `set newlines in values as \n and backslashes as \\ (for apps that expect single-line values)`)
+}
+
+func runExec(cmd *cobra.Command, args []string) error {
+	if cmd.ArgsLenAtDash() != 1 {
+		return fmt.Errorf("usage: lockr exec <path> -- <command> [args...]")
+	}
+
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	mapped, err := parseEnvMap(execEnvMap)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := fetchExportSecrets(path)
+	if err != nil {
+		return err
+	}
+	if len(mapped) > 0 {
+		extra, err := fetchMappedSecrets(mapped)
+		if err != nil {
+			return err
+		}
+		// Later variables win, so mapped names override derived ones
+		secrets = append(secrets, extra...)
+	}
+
+	env := os.Environ()
+	for _, v := range envVars(secrets) {
+		env = append(env, v.name+"="+v.value)
+	}
+
+	child := exec.Command(args[1], args[2:]...)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	if err := child.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return silentExit(cmd, exitErr.ExitCode())
+		}
+		return fmt.Errorf("failed to run %s: %w", args[1], err)
+	}
+
+	return nil
+}
+
+// envMapping is one --env-map entry
+type envMapping struct {
+	env  string
+	path string
+}
+
+// parseEnvMap parses NAME=path --env-map entries, resolving relative paths
+func parseEnvMap(entries []string) ([]envMapping, error) {
+	var mapped []envMapping
+	for _, entry := range entries {
+		name, path, ok := strings.Cut(entry, "=")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid --env-map entry %q (expected NAME=path)", entry)
+		}
+		full, err := buildPath(path)
+		if err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, envMapping{env: name, path: full})
+	}
+	return mapped, nil
+}
+
+// fetchMappedSecrets reads the --env-map parameters, failing if any of them
+// doesn't exist
+func fetchMappedSecrets(mapped []envMapping) ([]exportSecret, error) {
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	names := make([]string, len(mapped))
+	for i, m := range mapped {
+		names[i] = m.path
+	}
+	found, err := client.ReadSecretsByName(names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read --env-map secrets: %w", err)
+	}
+	byName := make(map[string]ssm.Secret, len(found))
+	for _, s := range found {
+		byName[s.Name] = s
+	}
+
+	result := make([]exportSecret, 0, len(mapped))
+	for _, m := range mapped {
+		s, ok := byName[m.path]
+		if !ok {
+			return nil, fmt.Errorf("--env-map %s: %s not found", m.env, m.path)
+		}
+		result = append(result, exportSecret{
+			Name:    s.Name,
+			Key:     s.Name,
+			Env:     m.env,
+			Value:   s.Value,
+			Type:    s.Type,
+			Version: s.Version,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportTemplate     string
+	exportTemplateFile string
+	exportDelims       string
+
+	// expandLists and escapeNewlines are shared by export and exec
+	expandLists    bool
+	escapeNewlines bool
+
+	// secureOnly is shared by export and read --all
+	secureOnly bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export <path>",
+	Short: "Export all secrets under a path",
+	Long: `Export every secret under a path (recursively) with decrypted values.
+
+Output formats (--output):
+  text   KEY='value' lines, one per secret (default)
+  json   object keyed by path relative to the export path
+  yaml   the same map as block-style YAML with sorted keys (diff-stable)
+  tfvars Terraform variables (name = "value"), e.g. for secrets.auto.tfvars
+
+Variable names are the relative path upper-cased with / . and - replaced by _
+(db/password -> DB_PASSWORD). StringList values stay comma-separated unless
+--expand-lists is given, which emits one numbered variable per element
+(HOSTS_0, HOSTS_1, ...).
+
+For tfvars, variable names are the relative path with / and . replaced by _
+(db/password -> db_password); any other character that isn't a letter,
+digit, _ or - becomes _, and a leading digit gets a _ prefix. Names that
+collide after this are an error. Values are escaped for HCL, including ${
+and %{ so they aren't read as template sequences. With --expand-lists,
+StringList values become HCL lists.
+
+With --secure-only, plain String and StringList parameters are skipped so
+only SecureString secrets are exported.
+
+Values are single-quoted for POSIX shells, so multi-line values
+(certificates, keys) keep their real newlines. With --escape-newlines each
+newline is written as the two characters \n, and each backslash as \\, so
+every variable stays on one line and can be un-escaped unambiguously; the
+quotes stay single, so consumers must do that themselves (e.g. printf '%b'
+in a shell, since single-quoted dotenv values aren't un-escaped).
+
+With --template or --template-file, the secrets are rendered through a Go
+text/template instead. The template receives the list of secrets, each with
+.Name (full path), .Key (relative path), .Env (variable name), .Value, .Type
+and .Version, and a 'get' function that returns a value by relative path.
+If the output itself contains {{ }} (e.g. another templating language),
+--template-delims sets different delimiters, given as "left right".
+
+Examples:
+  # Shell-sourceable variables
+  lockr export /myapp/prod > .env
+
+  # JSON object
+  lockr export /myapp/prod --output json
+
+  # YAML for a GitOps repo (sorted keys, block style)
+  lockr export /myapp/prod --output yaml --out-file secrets.yaml
+
+  # Terraform variables, picked up automatically at plan time
+  lockr export /myapp/prod --output tfvars --out-file secrets.auto.tfvars
+
+  # Only encrypted secrets, not plain config entries
+  lockr export /myapp/prod --secure-only > .env
+
+  # Inline template
+  lockr export /myapp/prod --template '{{range .}}{{.Env}}={{.Value}}{{"\n"}}{{end}}'
+
+  # Render a config file from a template
+  lockr export /myapp/prod --template-file app.conf.tmpl --out-file app.conf
+
+  # Keep {{ }} literal in the output; fields are written as << .Env >>
+  lockr export /myapp/prod --template-file values.tmpl --template-delims '<< >>'`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{tfvarsAnnotation: "true", outFileAnnotation: "true"},
+	RunE:        runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVar(&exportTemplate, "template", "", "render secrets with an inline Go template")
+	exportCmd.Flags().StringVar(&exportTemplateFile, "template-file", "", "render secrets with a Go template file")
+	exportCmd.Flags().StringVar(&exportDelims, "template-delims", "", "template action delimiters as \"left right\" (e.g. '<< >>'; default {{ }})")
+	exportCmd.Flags().BoolVar(&expandLists, "expand-lists", false, "export StringList elements as numbered variables (KEY_0, KEY_1, ...)")
+	exportCmd.Flags().BoolVar(&secureOnly, "secure-only", false, "only export SecureString parameters")
+	exportCmd.Flags().BoolVar(&escapeNewlines, "escape-newlines", false, `write newlines in values as \n (and backslashes as \\) so each variable is one line`)
+}
+
+// exportSecret is one secret as exposed to export formats and templates
+type exportSecret struct {
+	Name    string
+	Key     string
+	Env     string
+	Value   string
+	Type    string
+	Version int64
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportTemplate != "" && exportTemplateFile != "" {
+		return fmt.Errorf("--template and --template-file are mutually exclusive")
+	}
+	if exportDelims != "" && exportTemplate == "" && exportTemplateFile == "" {
+		return fmt.Errorf("--template-delims requires --template or --template-file")
+	}
+
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Parse the template before fetching so mistakes fail fast
+	tmpl, err := loadExportTemplate()
+	if err != nil {
+		return err
+	}
+
+	secrets, err := fetchExportSecrets(path)
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		tmpl.Funcs(template.FuncMap{"get": exportGetter(secrets)})
+		if err := tmpl.Execute(out, secrets); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+		return nil
+	}
+
+	switch cfg.Output {
+	case "tfvars":
+		return printTFVars(secrets)
+	case "json", "yaml":
+		values := make(map[string]string, len(secrets))
+		for _, s := range secrets {
+			values[s.Key] = s.Value
+		}
+		if err := printStructured(values); err != nil {
+			return err
+		}
+	default:
+		for _, v := range envVars(secrets) {
+			fmt.Fprintf(out, "%s=%s\n", v.name, shellQuote(v.value))
+		}
+	}
+
+	return nil
+}
+
+// fetchExportSecrets reads every secret under path, sorted by key
+func fetchExportSecrets(path string) ([]exportSecret, error) {
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var result []exportSecret
+	var readErr error
+	_ = spinner.New().
+		Title("Reading secrets...").
+		Action(func() {
+			secrets, err := client.ReadSecrets(path, true)
+			if err != nil {
+				readErr = err
+				return
+			}
+			for _, s := range secrets {
+				if secureOnly && s.Type != ssm.SecureStringType {
+					continue
+				}
+				key := relativeName(s.Name, path)
+				result = append(result, exportSecret{
+					Name:    s.Name,
+					Key:     key,
+					Env:     envName(key),
+					Value:   shownValue(s.Value),
+					Type:    s.Type,
+					Version: s.Version,
+				})
+			}
+		}).
+		Run()
+
+	if readErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to read secrets"))
+		return nil, fmt.Errorf("failed to read secrets: %w", readErr)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// loadExportTemplate parses --template or --template-file, or returns nil if
+// neither is set
+func loadExportTemplate() (*template.Template, error) {
+	text := exportTemplate
+	name := "template"
+	if exportTemplateFile != "" {
+		data, err := os.ReadFile(exportTemplateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template: %w", err)
+		}
+		text = string(data)
+		name = exportTemplateFile
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	left, right, err := parseTemplateDelims(exportDelims)
+	if err != nil {
+		return nil, err
+	}
+
+	// get is rebound to the fetched secrets before execution
+	tmpl, err := template.New(name).
+		Delims(left, right).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{"get": exportGetter(nil)}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// parseTemplateDelims splits --template-delims ("<< >>") into the left and
+// right delimiters. Empty means the default {{ }}.
+func parseTemplateDelims(s string) (string, string, error) {
+	if s == "" {
+		return "", "", nil
+	}
+	parts := strings.Fields(s)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid --template-delims %q: give the left and right delimiter separated by a space, e.g. '<< >>'", s)
+	}
+	if parts[0] == parts[1] {
+		return "", "", fmt.Errorf("invalid --template-delims %q: the left and right delimiters must differ", s)
+	}
+	return parts[0], parts[1], nil
+}
+
+// exportGetter returns the template 'get' function over secrets
+func exportGetter(secrets []exportSecret) func(string) (string, error) {
+	return func(key string) (string, error) {
+		for _, s := range secrets {
+			if s.Key == key {
+				return s.Value, nil
+			}
+		}
+		return "", fmt.Errorf("no secret %q", key)
+	}
+}
+
+// envVar is one environment variable produced from a secret
+type envVar struct {
+	name  string
+	value string
+}
+
+// envVars converts secrets to environment variables. StringList secrets are
+// split into numbered variables when --expand-lists is set, and newlines are
+// escaped when --escape-newlines is set.
+func envVars(secrets []exportSecret) []envVar {
+	vars := make([]envVar, 0, len(secrets))
+	for _, s := range secrets {
+		if expandLists && s.Type == "StringList" {
+			for i, item := range strings.Split(s.Value, ",") {
+				vars = append(vars, envVar{name: fmt.Sprintf("%s_%d", s.Env, i), value: envValue(item)})
+			}
+			continue
+		}
+		vars = append(vars, envVar{name: s.Env, value: envValue(s.Value)})
+	}
+	return vars
+}
+
+// envValue applies --escape-newlines to a value. Backslashes are escaped
+// first so a literal \n in the value doesn't read back as a newline.
+func envValue(v string) string {
+	if !escapeNewlines {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, "\r\n", "\n")
+	return strings.ReplaceAll(v, "\n", `\n`)
+}
+
+// envName converts a relative secret path to an environment variable name
+func envName(key string) string {
+	r := strings.NewReplacer("/", "_", "-", "_", ".", "_")
+	return strings.ToUpper(r.Replace(strings.TrimPrefix(key, "/")))
+}
+
+// printTFVars writes secrets as Terraform variable assignments
+func printTFVars(secrets []exportSecret) error {
+	seen := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		name := tfvarName(s.Key)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("%s and %s both become the Terraform variable %q", other, s.Name, name)
+		}
+		seen[name] = s.Name
+
+		if expandLists && s.Type == "StringList" {
+			items := strings.Split(s.Value, ",")
+			for i, item := range items {
+				items[i] = hclQuote(item)
+			}
+			fmt.Fprintf(out, "%s = [%s]\n", name, strings.Join(items, ", "))
+			continue
+		}
+		fmt.Fprintf(out, "%s = %s\n", name, hclQuote(s.Value))
+	}
+	return nil
+}
+
+// tfvarName converts a relative secret path to a Terraform variable name
+func tfvarName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimPrefix(key, "/") {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "_" + name
+	}
+	return name
+}
+
+// hclQuote double-quotes s as an HCL string, escaping template sequences
+// so the value is used literally
+func hclQuote(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+		"${", "$${",
+		"%{", "%%{",
+	)
+	return `"` + r.Replace(s) + `"`
+}
+
+// shellQuote single-quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
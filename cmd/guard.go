@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	pathpkg "path"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"golang.org/x/term"
+)
+
+// confirmedRegion is the --confirm-region value
+var confirmedRegion string
+
+// allowAbsolute is the --allow-absolute value
+var allowAbsolute bool
+
+// regionsConfirmed records regions the user has already accepted this run,
+// so a command touching many secrets asks once
+var (
+	regionsConfirmed   = map[string]bool{}
+	regionsConfirmedMu sync.Mutex
+)
+
+// confirmRegion guards a mutating command against running in the wrong
+// region. When expected_region is set and client resolved a different
+// region, the user must confirm in a terminal, or pass --confirm-region with
+// the actual region when not in one.
+func confirmRegion(client *ssm.Client) error {
+	region := client.AWSConfig().Region
+	if cfg.ExpectedRegion == "" || region == cfg.ExpectedRegion {
+		return nil
+	}
+
+	regionsConfirmedMu.Lock()
+	defer regionsConfirmedMu.Unlock()
+	if regionsConfirmed[region] || confirmedRegion == region {
+		return nil
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("region is %s but expected_region is %s; pass --confirm-region %s to change secrets there", region, cfg.ExpectedRegion, region)
+	}
+
+	fmt.Fprintln(statusOut, ui.Warningf("Region is %s, but expected_region is %s", region, cfg.ExpectedRegion))
+	var confirmed bool
+	confirm := huh.NewConfirm().
+		Title(fmt.Sprintf("Make changes in %s anyway?", region)).
+		Value(&confirmed)
+	confirm.WithTheme(ui.Theme())
+	if err := confirm.Run(); err != nil {
+		return err
+	}
+	if !confirmed {
+		return fmt.Errorf("cancelled: region %s is not the expected region %s", region, cfg.ExpectedRegion)
+	}
+	regionsConfirmed[region] = true
+	return nil
+}
+
+// checkRequiredPrefix enforces require_prefix on a path resolved by
+// buildPath: it must be the configured prefix or under it, unless
+// --allow-absolute is given. In shared accounts this keeps a mistyped
+// absolute path from reaching into another team's namespace.
+func checkRequiredPrefix(path string) error {
+	if !cfg.RequirePrefix || allowAbsolute {
+		return nil
+	}
+	root := prefixRoot()
+	clean := pathpkg.Clean(path)
+	if clean != root && !strings.HasPrefix(clean, root+"/") {
+		return fmt.Errorf("%s is outside the prefix %s and require_prefix is set; pass --allow-absolute to use it anyway", path, root)
+	}
+	return nil
+}
+
+// prefixRoot returns the configured prefix as an absolute path
+func prefixRoot() string {
+	return "/" + strings.Trim(cfg.Prefix, "/")
+}
+
+// defaultPath resolves the optional path argument of commands that otherwise
+// cover every secret: without one it is "/", or the prefix when
+// require_prefix is set (and --allow-absolute isn't)
+func defaultPath(args []string) (string, error) {
+	switch {
+	case len(args) > 0:
+		return buildPath(args[0])
+	case cfg.RequirePrefix && !allowAbsolute:
+		return buildPath(prefixRoot())
+	default:
+		return buildPath("/")
+	}
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyDiff   bool
+	historyReveal bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history <path> [--diff <from> <to>]",
+	Short: "Show the version history of a secret",
+	Long: `Show the version history of a secret.
+
+With --diff, compares the values of two versions line by line. Values are
+masked unless --reveal is given; without it, only the changed line numbers,
+sizes and fingerprints are shown.
+
+Examples:
+  # List versions
+  lockr history /myapp/prod/api-key
+
+  # What changed between versions 3 and 5 (masked)
+  lockr history /myapp/prod/config --diff v3 v5
+
+  # Show the actual changed content
+  lockr history /myapp/prod/config --diff 3 5 --reveal`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().BoolVar(&historyDiff, "diff", false, "diff the values of two versions")
+	historyCmd.Flags().BoolVar(&historyReveal, "reveal", false, "show value content in --diff output")
+}
+
+// diffLine is one line of a line-level value diff
+type diffLine struct {
+	Op   string `json:"op"` // "-", "+" or " "
+	Line int    `json:"line"`
+	Text string `json:"text,omitempty"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyDiff {
+		if len(args) != 3 {
+			return fmt.Errorf("--diff requires two versions: lockr history <path> --diff <from> <to>")
+		}
+	} else if len(args) != 1 {
+		return fmt.Errorf("versions can only be given with --diff")
+	}
+
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var versions []ssm.SecretVersion
+	var histErr error
+	_ = spinner.New().
+		Title("Fetching history...").
+		Action(func() {
+			// Values are only decrypted when they're needed for a diff
+			versions, histErr = client.History(path, historyDiff)
+		}).
+		Run()
+
+	if histErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to get history"))
+		return fmt.Errorf("failed to get history: %w", histErr)
+	}
+
+	if historyDiff {
+		return runHistoryDiff(path, versions, args[1], args[2])
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if err := printStructured(versions); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader("History: "+path))
+		fmt.Fprintln(out)
+
+		rows := make([][]string, 0, len(versions))
+		for i := len(versions) - 1; i >= 0; i-- {
+			v := versions[i]
+			modified := ""
+			if v.LastModified != nil {
+				modified = v.LastModified.Local().Format("2006-01-02 15:04:05")
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", v.Version),
+				modified,
+				v.LastModifiedUser,
+				strings.Join(v.Labels, ", "),
+			})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Version", "Modified", "By", "Labels"}, rows))
+		fmt.Fprintln(out)
+	}
+
+	return nil
+}
+
+func runHistoryDiff(path string, versions []ssm.SecretVersion, fromArg, toArg string) error {
+	from, err := findVersion(versions, fromArg)
+	if err != nil {
+		return err
+	}
+	to, err := findVersion(versions, toArg)
+	if err != nil {
+		return err
+	}
+
+	lines := lineDiff(splitLines(from.Value), splitLines(to.Value))
+	changed := from.Value != to.Value
+
+	switch cfg.Output {
+	case "json", "yaml":
+		changes := []diffLine{}
+		for _, l := range lines {
+			if l.Op == " " {
+				continue
+			}
+			if !historyReveal {
+				l.Text = ""
+			}
+			changes = append(changes, l)
+		}
+		output := map[string]interface{}{
+			"path":    path,
+			"from":    map[string]interface{}{"version": from.Version, "size": len(from.Value), "sha256": fingerprint(from.Value)},
+			"to":      map[string]interface{}{"version": to.Version, "size": len(to.Value), "sha256": fingerprint(to.Value)},
+			"changed": changed,
+			"lines":   changes,
+		}
+		if err := printStructured(output); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader(fmt.Sprintf("%s: v%d → v%d", path, from.Version, to.Version)))
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  v%-4d %d bytes  sha256:%s\n", from.Version, len(from.Value), fingerprint(from.Value))
+		fmt.Fprintf(out, "  v%-4d %d bytes  sha256:%s\n", to.Version, len(to.Value), fingerprint(to.Value))
+		fmt.Fprintln(out)
+
+		if !changed {
+			fmt.Fprintln(out, ui.Success("Values are identical"))
+			fmt.Fprintln(out)
+			return nil
+		}
+
+		for _, l := range lines {
+			if l.Op == " " {
+				continue
+			}
+			text := fmt.Sprintf("(line %d masked)", l.Line)
+			if historyReveal {
+				text = l.Text
+			}
+			if l.Op == "-" {
+				fmt.Fprintln(out, ui.Error("- "+text))
+			} else {
+				fmt.Fprintln(out, ui.Success("+ "+text))
+			}
+		}
+		fmt.Fprintln(out)
+
+		if !historyReveal {
+			fmt.Fprintln(statusOut, ui.Subtle("Values are masked. Use --reveal to show content."))
+			fmt.Fprintln(statusOut)
+		}
+	}
+
+	return nil
+}
+
+// findVersion returns the version matching arg ("3" or "v3")
+func findVersion(versions []ssm.SecretVersion, arg string) (*ssm.SecretVersion, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(strings.ToLower(arg), "v"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q", arg)
+	}
+	for i := range versions {
+		if versions[i].Version == n {
+			return &versions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("version %d not found in history", n)
+}
+
+// splitLines splits a value into lines, ignoring a single trailing newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// lineDiff returns an LCS-based line diff of a and b. Line numbers refer to
+// a for removed and unchanged lines and to b for added lines.
+func lineDiff(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{Op: " ", Line: i + 1, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{Op: "-", Line: i + 1, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{Op: "+", Line: j + 1, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{Op: "-", Line: i + 1, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{Op: "+", Line: j + 1, Text: b[j]})
+	}
+	return lines
+}
+
+// fingerprint returns a short SHA-256 of a value, safe to display
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:12]
+}
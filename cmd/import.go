@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat  string
+	importApprove bool
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <file> <path>",
+	Short: "Import secrets from a dotenv or JSON file",
+	Long: `Import key/value pairs from a file as secrets under a path.
+
+Each key becomes <path>/<key>. The file format is detected automatically: if
+the file parses as a JSON object it's used as key/value pairs (non-string
+values are stored as their JSON text), otherwise it's read as dotenv.
+
+Dotenv handling: blank lines and # comments are skipped, an "export " prefix
+is allowed, values may be single-quoted (literal), double-quoted (with \n, \"
+and \\ escapes, and may span lines) or unquoted (a trailing " # comment" is
+removed).
+
+Like apply, import shows a plan (create/update/unchanged) and asks before
+changing anything; --auto-approve skips the prompt. Writes run up to
+--concurrency at a time; a failure doesn't stop the others unless --fail-fast
+is given. A summary of the outcome is printed at the end (only the summary is
+on stdout with --output json).
+
+Examples:
+  # Import a .env file
+  lockr import .env /myapp/prod
+
+  # Force the format
+  lockr import secrets.txt /myapp/prod --format dotenv
+
+  # Non-interactive
+  lockr import config.json /myapp/prod --auto-approve`,
+	Args: cobra.ExactArgs(2),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importFormat, "format", "auto", "input format: auto, json, or dotenv")
+	importCmd.Flags().BoolVar(&importApprove, "auto-approve", false, "import without asking for confirmation")
+	importCmd.Flags().IntVar(&planConcurrency, "concurrency", 5, "maximum number of secrets written at once")
+	importCmd.Flags().BoolVar(&planFailFast, "fail-fast", false, "stop starting new writes after the first failure")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+
+	values, err := parseImport(data, importFormat)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", args[0], err)
+	}
+	if len(values) == 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("No values found in %s", args[0]))
+		return nil
+	}
+
+	base, err := buildPath(args[1])
+	if err != nil {
+		return err
+	}
+	base = strings.TrimSuffix(base, "/")
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := &manifest{}
+	for _, k := range keys {
+		// SSM doesn't store empty values
+		if values[k] == "" {
+			fmt.Fprintln(statusOut, ui.Warningf("Skipping %s: empty value", k))
+			continue
+		}
+		m.Secrets = append(m.Secrets, manifestSecret{
+			Path:  base + "/" + strings.TrimPrefix(k, "/"),
+			Value: values[k],
+		})
+	}
+
+	if err := resolveManifest(m); err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var p *applyPlanFile
+	var planErr error
+	_ = spinner.New().
+		Title("Computing plan...").
+		Action(func() {
+			p, planErr = computePlan(client, m)
+		}).
+		Run()
+
+	if planErr != nil {
+		fmt.Println(ui.Error("Failed to compute plan"))
+		return fmt.Errorf("failed to compute plan: %w", planErr)
+	}
+
+	printPlan(p)
+	return runPlan(client, "import", p, importApprove)
+}
+
+// parseImport parses data as format (auto, json or dotenv) into key/value
+// pairs
+func parseImport(data []byte, format string) (map[string]string, error) {
+	switch format {
+	case "json":
+		return parseJSONValues(data)
+	case "dotenv":
+		return parseDotenv(data)
+	case "auto":
+		if values, err := parseJSONValues(data); err == nil {
+			return values, nil
+		}
+		return parseDotenv(data)
+	default:
+		return nil, fmt.Errorf("invalid --format %q (use auto, json or dotenv)", format)
+	}
+}
+
+// parseJSONValues reads a JSON object. String values are used as-is; other
+// values are stored as their JSON text.
+func parseJSONValues(data []byte) (map[string]string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(obj))
+	for k, raw := range obj {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			values[k] = s
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, raw); err != nil {
+			return nil, err
+		}
+		values[k] = compact.String()
+	}
+	return values, nil
+}
+
+// parseDotenv reads KEY=value lines
+func parseDotenv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected KEY=value", lineNo)
+		}
+		raw = strings.TrimSpace(raw)
+
+		switch {
+		case strings.HasPrefix(raw, `"`):
+			// Double-quoted values may continue over several lines
+			start := lineNo
+			end := closingQuote(raw)
+			for end < 0 {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double quote", start)
+				}
+				lineNo++
+				raw += "\n" + scanner.Text()
+				end = closingQuote(raw)
+			}
+			values[key] = unescapeDoubleQuoted(raw[1:end])
+
+		case strings.HasPrefix(raw, "'"):
+			end := strings.Index(raw[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single quote", lineNo)
+			}
+			values[key] = raw[1 : end+1]
+
+		default:
+			if i := strings.Index(raw, " #"); i >= 0 {
+				raw = strings.TrimSpace(raw[:i])
+			}
+			values[key] = raw
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// closingQuote returns the index of the unescaped quote closing the
+// double-quoted string s, or -1 if it isn't closed yet
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted expands \n, \", \\ and similar escapes
+func unescapeDoubleQuoted(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -1,9 +1,17 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	pathpkg "path"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/huh/spinner"
@@ -15,15 +23,51 @@ import (
 var (
 	listRecursive   bool
 	listInteractive bool
+	listModifiedBy  string
+	listWithTags    bool
+	listTagsWidth   int
+	listExpandTags  bool
+	listMissingTag  string
+	listMatch       string
+	listName        string
+	listWithValue   bool
+	listReveal      bool
+	listValueLimit  int
+	listForce       bool
+	listNamesOnly   bool
+	listWithKey     bool
+	listGroupByKey  bool
+	listDefaultKey  bool
+	listNested      bool
+	listFields      string
+	listKMSKey      string
+	listFormat      string
+
+	// listNameMatch is the compiled --match/--name filter (nil = no filter)
+	listNameMatch func(string) bool
+
+	// listFilters are the SSM-side filters (--kms-key)
+	listFilters []ssm.Filter
+
+	// listFieldNames are the parsed --fields (nil = all fields)
+	listFieldNames []string
+
+	// listDefaultKeyMatch reports whether a KMS key ID is the AWS managed
+	// key, for --default-key-only
+	listDefaultKeyMatch func(string) bool
 )
 
+// listTagWorkers bounds concurrent ListTagsForResource calls for --with-tags
+const listTagWorkers = 8
+
 var listCmd = &cobra.Command{
-	Use:   "list [path]",
+	Use:   "list [path...]",
 	Short: "List secrets in SSM Parameter Store",
 	Long: `List secrets in AWS SSM Parameter Store.
 
 Without a path, lists ALL secrets (that you have access to) with interactive fuzzy search.
-With a path, lists secrets at that path.
+With a path, lists secrets at that path. Several paths can be given; they are
+fetched in parallel and shown as separate sections.
 
 Examples:
   # List ALL secrets with fuzzy search (interactive)
@@ -35,13 +79,67 @@ Examples:
   # List recursively
   lockr list /myapp --recursive
 
+  # Which KMS key each secret uses, or grouped by key (audit secrets on the
+  # AWS managed key that should use a customer managed one)
+  lockr list /myapp --recursive --with-key
+  lockr list / --recursive --group-by-key
+
+  # Only SecureStrings encrypted with one (e.g. deprecated) key
+  lockr list / --recursive --kms-key alias/old-key --names-only
+
+  # Only SecureStrings still on the AWS managed key, e.g. to rekey them
+  lockr list / --recursive --default-key-only --names-only |
+    xargs -n1 lockr rekey --force --kms-key alias/myapp
+
+  # Just the names, for piping (e.g. into lockr delete --stdin)
+  lockr list /myapp/old --recursive --names-only
+
+  # Snapshot the live versions as a lockfile (path@version lines), then later
+  # check nothing drifted
+  lockr list /myapp/prod --recursive --format path-version > secrets.lock
+  lockr verify --lockfile secrets.lock
+
+  # List several paths at once
+  lockr list /app1/prod /app2/prod /shared
+
   # Force interactive mode on a path
   lockr list /myapp -i
 
-  # Output as JSON
-  lockr list /myapp/prod --output json`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runList,
+  # Interactive list grouped by top-level path segment
+  lockr list --group prefix
+
+  # Only secrets last modified by a given IAM principal (ARN or substring)
+  lockr list / --recursive --modified-by role/ci-deployer
+
+  # Include tags (truncated to fit; --expand-tags shows them fully)
+  lockr list /myapp/prod --with-tags
+
+  # Precise name filtering for scripts: exact, prefix, contains, glob or regex
+  lockr list / --recursive --match prefix --name /myapp/prod/
+  lockr list / --recursive --match glob --name '/myapp/*/db-*'
+  lockr list / --recursive --match regex --name 'api-key$'
+
+  # Secrets without an owner tag (for tagging compliance)
+  lockr list / --recursive --missing-tag owner
+
+  # Include values (masked; --reveal shows them) for a small tree
+  lockr list /myapp/prod/config --with-value --reveal
+
+  # Output as JSON (streamed page by page for a single path, so even very
+  # large accounts list in bounded memory)
+  lockr list / --recursive --output json
+
+  # Nested JSON objects by path segment: {"myapp": {"db": {"password": {...}}}}
+  lockr list /myapp --recursive --nested --output json
+
+  # Only some fields in JSON output (saves a jq step)
+  lockr list /myapp --recursive --output json --fields name,version
+
+  # Tab-separated with a header row (pastes into spreadsheets and tickets)
+  lockr list /myapp/prod --output tsv`,
+	Args:        cobra.ArbitraryArgs,
+	Annotations: map[string]string{tsvAnnotation: "true", outFileAnnotation: "true"},
+	RunE:        runList,
 }
 
 func init() {
@@ -49,74 +147,517 @@ func init() {
 
 	listCmd.Flags().BoolVarP(&listRecursive, "recursive", "r", false, "list recursively")
 	listCmd.Flags().BoolVarP(&listInteractive, "interactive", "i", false, "enable interactive fuzzy search")
+	listCmd.Flags().StringVar(&listModifiedBy, "modified-by", "", "only secrets last modified by this IAM principal (ARN or substring)")
+	listCmd.Flags().BoolVar(&listWithTags, "with-tags", false, "include each secret's tags (one extra API call per secret)")
+	listCmd.Flags().IntVar(&listTagsWidth, "tags-width", 40, "truncate the Tags column to this many characters")
+	listCmd.Flags().BoolVar(&listExpandTags, "expand-tags", false, "show tags in full instead of truncating")
+	listCmd.Flags().StringVar(&listMissingTag, "missing-tag", "", "only secrets that don't have this tag key")
+	listCmd.Flags().StringVar(&listName, "name", "", "only secrets whose full name matches this (see --match)")
+	listCmd.Flags().StringVar(&listMatch, "match", "contains", "how --name is matched: exact, prefix, contains, glob, or regex")
+	listCmd.Flags().BoolVar(&listWithValue, "with-value", false, "include a Value column (masked unless --reveal)")
+	listCmd.Flags().BoolVar(&listReveal, "reveal", false, "with --with-value, show values instead of ***")
+	listCmd.Flags().IntVar(&listValueLimit, "value-limit", 25, "--with-value --reveal refuses to decrypt more than this many secrets without --force")
+	listCmd.Flags().BoolVar(&listForce, "force", false, "with --with-value --reveal, decrypt values even above --value-limit")
+	listCmd.Flags().BoolVar(&listNamesOnly, "names-only", false, "print only full secret names, one per line (e.g. to pipe into delete --stdin)")
+	listCmd.Flags().BoolVar(&listWithKey, "with-key", false, "include each secret's KMS key")
+	listCmd.Flags().BoolVar(&listGroupByKey, "group-by-key", false, "group secrets by KMS key (flags ones using the AWS managed key)")
+	listCmd.Flags().BoolVar(&listNested, "nested", false, "with --output json/yaml, nest secrets by path segment instead of a flat list")
+	listCmd.Flags().StringVar(&listFields, "fields", "", "with --output json/yaml, only include these fields (comma-separated, e.g. name,version)")
+	listCmd.Flags().StringVar(&listKMSKey, "kms-key", "", "only SecureStrings encrypted with this KMS key (alias, key ID or ARN)")
+	listCmd.Flags().BoolVar(&listDefaultKey, "default-key-only", false, "only SecureStrings encrypted with the AWS managed key (alias/aws/ssm)")
+	listCmd.Flags().StringVar(&listFormat, "format", "", "path-version: print path@version lines (or a {path: version} map with --output json/yaml) as a lockfile for verify")
+	listCmd.Flags().StringVar(&pickerGroup, "group", "none", "interactive list order: none, alpha, or prefix (group by top-level segment)")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	// Default to root path if none provided
-	path := "/"
-	if len(args) > 0 {
-		path = buildPath(args[0])
+	if err := validatePickerGroup(); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("match") && listName == "" {
+		return fmt.Errorf("--match requires --name")
+	}
+	if listName != "" {
+		match, err := nameMatcher(listMatch, listName)
+		if err != nil {
+			return err
+		}
+		listNameMatch = match
+	}
+
+	// Default to root path (or the required prefix) if none provided
+	var paths []string
+	if len(args) == 0 {
+		root, err := defaultPath(nil)
+		if err != nil {
+			return err
+		}
+		paths = []string{root}
+	} else {
+		paths = make([]string, len(args))
+		for i, arg := range args {
+			var err error
+			if paths[i], err = buildPath(arg); err != nil {
+				return err
+			}
+		}
 	}
 
 	// If no path provided, default to recursive and interactive
 	noPathProvided := len(args) == 0
 	if noPathProvided {
 		listRecursive = true
-		listInteractive = true
+		listInteractive = !listNamesOnly && !listGroupByKey && listFormat == ""
+	}
+	if listGroupByKey && listInteractive {
+		return fmt.Errorf("--group-by-key cannot be used with --interactive")
+	}
+	if listNamesOnly && (listInteractive || listWithValue) {
+		return fmt.Errorf("--names-only cannot be used with --interactive or --with-value")
+	}
+	if listFormat != "" {
+		if listFormat != "path-version" {
+			return fmt.Errorf("invalid --format %q (use path-version)", listFormat)
+		}
+		if listInteractive || listWithValue || listNamesOnly || listGroupByKey || listNested || listFields != "" || cfg.Output == "tsv" {
+			return fmt.Errorf("--format cannot be used with --interactive, --with-value, --names-only, --group-by-key, --nested, --fields or --output tsv")
+		}
+	}
+	if listFields != "" {
+		if cfg.Output != "json" && cfg.Output != "yaml" {
+			return fmt.Errorf("--fields requires --output json or yaml")
+		}
+		fields, err := parseFields(listFields, jsonFieldNames(reflect.TypeOf(ssm.SecretMetadata{})))
+		if err != nil {
+			return err
+		}
+		listFieldNames = fields
+	}
+	if listNested && (cfg.Output != "json" && cfg.Output != "yaml" || listGroupByKey || listNamesOnly) {
+		return fmt.Errorf("--nested requires --output json or yaml and can't be used with --group-by-key or --names-only")
 	}
 
-	client, err := ssm.NewClient(cfg.Region)
+	client, err := newClient(cfg.Region)
 	if err != nil {
 		return fmt.Errorf("failed to create SSM client: %w", err)
 	}
 
-	var secrets []ssm.SecretMetadata
-	var listErr error
+	if listDefaultKey {
+		if listDefaultKeyMatch, err = defaultKeyMatcher(client); err != nil {
+			return err
+		}
+	}
+	if listKMSKey != "" {
+		listFilters = []ssm.Filter{ssm.KeyIDFilter(kmsKeyForms(client, listKMSKey)...)}
+	}
+
+	if canStreamList(paths) {
+		return streamList(client, paths[0])
+	}
+
+	results := make([][]ssm.SecretMetadata, len(paths))
+	errs := make([]error, len(paths))
 	_ = spinner.New().
 		Title("Fetching secrets...").
 		Action(func() {
-			secrets, listErr = client.ListSecrets(path, listRecursive)
+			var wg sync.WaitGroup
+			for i, path := range paths {
+				wg.Add(1)
+				go func(i int, path string) {
+					defer wg.Done()
+					results[i], errs[i] = listPath(client, path)
+				}(i, path)
+			}
+			wg.Wait()
 		}).
 		Run()
 
-	if listErr != nil {
-		fmt.Println(ui.Error("Failed to list secrets"))
-		return fmt.Errorf("failed to list secrets: %w", listErr)
+	for i, path := range paths {
+		if errs[i] != nil {
+			fmt.Fprintln(statusOut, ui.Error("Failed to list secrets"))
+			return fmt.Errorf("failed to list secrets at %s: %w", path, errs[i])
+		}
 	}
 
-	if len(secrets) == 0 {
-		fmt.Println(ui.Warningf("No secrets found at %s", path))
+	if listWithValue {
+		if err := fillListValues(client, results); err != nil {
+			return err
+		}
+	}
+
+	var all []ssm.SecretMetadata
+	for i := range paths {
+		all = append(all, results[i]...)
+	}
+
+	if listFormat == "path-version" {
+		return printPathVersions(all)
+	}
+
+	if listNamesOnly {
+		// Nothing but names on stdout, so it can be piped
+		if len(all) == 0 {
+			fmt.Fprintln(os.Stderr, ui.Warningf("No secrets found at %s", strings.Join(paths, ", ")))
+		}
+		for _, s := range all {
+			fmt.Fprintln(out, s.Name)
+		}
+		return nil
+	}
+
+	if len(all) == 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("No secrets found at %s", strings.Join(paths, ", ")))
 		return nil
 	}
 
 	switch cfg.Output {
-	case "json":
-		data, err := json.MarshalIndent(secrets, "", "  ")
+	case "json", "yaml":
+		v, err := listRecords(all)
 		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
+			return err
+		}
+		if listNested {
+			flat := make(map[string]interface{}, len(all))
+			for _, s := range all {
+				if flat[s.Name], err = listRecord(s); err != nil {
+					return err
+				}
+			}
+			if v, err = nestPaths(flat); err != nil {
+				return err
+			}
+		} else if listGroupByKey {
+			groups := make(map[string]interface{})
+			for key, secrets := range groupByKey(all) {
+				if groups[key], err = listRecords(secrets); err != nil {
+					return err
+				}
+			}
+			v = groups
+		} else if len(paths) > 1 {
+			byPath := make(map[string]interface{}, len(paths))
+			for i, path := range paths {
+				if byPath[path], err = listRecords(results[i]); err != nil {
+					return err
+				}
+			}
+			v = byPath
 		}
-		fmt.Println(string(data))
+		if err := printStructured(v); err != nil {
+			return err
+		}
+	case "tsv":
+		printListTSV(all)
 	default:
-		fmt.Println()
-		fmt.Println(ui.Banner("lockr", "secrets manager for AWS SSM Parameter Store"))
+		fmt.Fprintln(statusOut)
+		fmt.Fprintln(statusOut, ui.Banner("lockr", "secrets manager for AWS SSM Parameter Store"))
 
 		// Interactive fuzzy search mode
 		if listInteractive {
-			return runInteractiveList(secrets)
+			return runInteractiveList(all)
+		}
+
+		if listGroupByKey {
+			return runKeyGroupedList(all)
+		}
+
+		// Standard table output, one section per path
+		for i, path := range paths {
+			if len(results[i]) == 0 {
+				fmt.Fprintln(statusOut, ui.Warningf("No secrets found at %s", path))
+				continue
+			}
+			if err := runTableList(results[i], path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// canStreamList reports whether the listing can be written as it's fetched:
+// JSON output for one path, without options that need every secret first
+// (--modified-by, tags, values)
+func canStreamList(paths []string) bool {
+	return cfg.Output == "json" && len(paths) == 1 && !listInteractive && !listNamesOnly && !listNested && listFormat == "" &&
+		!listDescribe() && !listWithTags && listMissingTag == "" && !listWithValue
+}
+
+// listDescribe reports whether the listing needs DescribeParameters, which
+// returns the fields GetParametersByPath lacks (last modified user, KMS key)
+func listDescribe() bool {
+	return listModifiedBy != "" || listWithKey || listGroupByKey || listDefaultKey
+}
+
+// streamList writes the secrets at path as a JSON array page by page, so
+// memory stays bounded for very large accounts
+func streamList(client *ssm.Client, path string) error {
+	var w jsonArrayWriter
+	err := client.WalkSecrets(path, listRecursive, func(meta ssm.SecretMetadata) error {
+		if listNameMatch != nil && !listNameMatch(meta.Name) {
+			return nil
+		}
+		record, err := listRecord(meta)
+		if err != nil {
+			return err
+		}
+		return w.Write(record)
+	}, listFilters...)
+	// Leave the array unterminated after a failed walk, so the partial
+	// output is invalid JSON rather than a silently short list
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to list secrets"))
+		return fmt.Errorf("failed to list secrets at %s: %w", path, err)
+	}
+
+	if w.count == 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("No secrets found at %s", path))
+	}
+	return nil
+}
+
+// printPathVersions prints secrets as a lockfile: path@version lines sorted
+// by path, or a {path: version} map with --output json/yaml
+func printPathVersions(secrets []ssm.SecretMetadata) error {
+	versions := make(map[string]int64, len(secrets))
+	for _, s := range secrets {
+		versions[s.Name] = s.Version
+	}
+	if len(versions) == 0 {
+		fmt.Fprintln(statusOut, ui.Warning("No secrets found, the lockfile is empty"))
+	}
+	if cfg.Output != "text" {
+		return printStructured(versions)
+	}
+
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s@%d\n", name, versions[name])
+	}
+	return nil
+}
+
+// listRecord is a secret as it appears in structured output: everything,
+// or only the --fields
+func listRecord(s ssm.SecretMetadata) (interface{}, error) {
+	if listFieldNames == nil {
+		return s, nil
+	}
+	return selectFields(s, listFieldNames)
+}
+
+// listRecords applies listRecord to secrets, as a list that is never null
+func listRecords(secrets []ssm.SecretMetadata) (interface{}, error) {
+	if listFieldNames == nil {
+		if secrets == nil {
+			return []ssm.SecretMetadata{}, nil
+		}
+		return secrets, nil
+	}
+	records := make([]interface{}, len(secrets))
+	for i, s := range secrets {
+		var err error
+		if records[i], err = selectFields(s, listFieldNames); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// listPath lists the secrets at one path, applying --modified-by
+func listPath(client *ssm.Client, path string) ([]ssm.SecretMetadata, error) {
+	var secrets []ssm.SecretMetadata
+	var err error
+	if listDescribe() {
+		// Only DescribeParameters returns the last modified user and KMS key
+		secrets, err = client.DescribeSecrets(path, listRecursive, listFilters...)
+		if err != nil {
+			return nil, err
+		}
+		if listModifiedBy != "" {
+			secrets = filterModifiedBy(secrets, listModifiedBy)
+		}
+		if listDefaultKeyMatch != nil {
+			secrets = filterDefaultKey(secrets, listDefaultKeyMatch)
+		}
+	} else {
+		secrets, err = client.ListSecrets(path, listRecursive, listFilters...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if listNameMatch != nil {
+		secrets = filterName(secrets, listNameMatch)
+	}
+
+	if listWithTags || listMissingTag != "" {
+		if err := fetchListTags(client, secrets); err != nil {
+			return nil, err
+		}
+	}
+	if listMissingTag != "" {
+		secrets = filterMissingTag(secrets, listMissingTag)
+	}
+	return secrets, nil
+}
+
+// fillListValues fills in the Value column for --with-value. Without
+// --reveal every value is shown as *** and nothing is read; with it, values
+// are decrypted, up to --value-limit secrets unless --force.
+func fillListValues(client *ssm.Client, results [][]ssm.SecretMetadata) error {
+	if !listReveal {
+		for _, secrets := range results {
+			for i := range secrets {
+				secrets[i].Value = "***"
+			}
+		}
+		return nil
+	}
+
+	var names []string
+	for _, secrets := range results {
+		for _, s := range secrets {
+			names = append(names, s.Name)
 		}
+	}
+	if len(names) > listValueLimit && !listForce {
+		return fmt.Errorf("--with-value --reveal would decrypt %d secrets (limit %d); narrow the path, raise --value-limit, or pass --force", len(names), listValueLimit)
+	}
 
-		// Standard table output
-		return runTableList(secrets, path)
+	var secrets []ssm.Secret
+	var readErr error
+	_ = spinner.New().
+		Title("Reading values...").
+		Action(func() {
+			secrets, readErr = client.ReadSecretsByName(names)
+		}).
+		Run()
+	if readErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to read values"))
+		return fmt.Errorf("failed to read values: %w", readErr)
 	}
 
+	values := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		values[s.Name] = s.Value
+	}
+	for _, secrets := range results {
+		for i := range secrets {
+			secrets[i].Value = values[secrets[i].Name]
+		}
+	}
 	return nil
 }
 
+// nameMatcher returns a predicate matching secret names against name
+// according to mode
+func nameMatcher(mode, name string) (func(string) bool, error) {
+	switch mode {
+	case "exact":
+		return func(s string) bool { return s == name }, nil
+	case "prefix":
+		return func(s string) bool { return strings.HasPrefix(s, name) }, nil
+	case "contains":
+		return func(s string) bool { return strings.Contains(s, name) }, nil
+	case "glob":
+		if _, err := pathpkg.Match(name, ""); err != nil {
+			return nil, fmt.Errorf("invalid --name glob %q: %w", name, err)
+		}
+		return func(s string) bool {
+			ok, _ := pathpkg.Match(name, s)
+			return ok
+		}, nil
+	case "regex":
+		re, err := regexp.Compile(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --name regex: %w", err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, fmt.Errorf("invalid --match %q (use exact, prefix, contains, glob or regex)", mode)
+	}
+}
+
+// filterName keeps secrets whose full name satisfies match
+func filterName(secrets []ssm.SecretMetadata, match func(string) bool) []ssm.SecretMetadata {
+	filtered := make([]ssm.SecretMetadata, 0, len(secrets))
+	for _, s := range secrets {
+		if match(s.Name) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// filterMissingTag keeps secrets that don't have the tag key
+func filterMissingTag(secrets []ssm.SecretMetadata, key string) []ssm.SecretMetadata {
+	filtered := make([]ssm.SecretMetadata, 0, len(secrets))
+	for _, s := range secrets {
+		if _, ok := s.Tags[key]; !ok {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// fetchListTags fills in Tags for each secret, a few at a time
+func fetchListTags(client *ssm.Client, secrets []ssm.SecretMetadata) error {
+	sem := make(chan struct{}, listTagWorkers)
+	errs := make([]error, len(secrets))
+	var wg sync.WaitGroup
+	for i := range secrets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			tags, err := client.GetTags(secrets[i].Name)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", secrets[i].Name, err)
+				return
+			}
+			secrets[i].Tags = redactTags(tags)
+		}(i)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// formatTagsCell renders tags for the Tags column, truncated to --tags-width
+// unless --expand-tags is set
+func formatTagsCell(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for _, row := range sortedKeyValueRows(tags) {
+		pairs = append(pairs, row[0]+"="+row[1])
+	}
+	cell := strings.Join(pairs, ", ")
+
+	if listExpandTags || listTagsWidth <= 0 {
+		return cell
+	}
+	if r := []rune(cell); len(r) > listTagsWidth {
+		if listTagsWidth == 1 {
+			return "…"
+		}
+		return string(r[:listTagsWidth-1]) + "…"
+	}
+	return cell
+}
+
 func runInteractiveList(secrets []ssm.SecretMetadata) error {
-	items := make([]pickItem, len(secrets))
+	names := make([]string, len(secrets))
 	for i, s := range secrets {
-		items[i] = pickItem{display: s.Name, search: s.Name, value: s.Name}
+		names[i] = s.Name
 	}
+	items := secretPickItems(names)
 
 	fmt.Println()
 	fmt.Println(ui.Infof("Found %d secrets", len(secrets)))
@@ -130,7 +671,7 @@ func runInteractiveList(secrets []ssm.SecretMetadata) error {
 
 	for _, s := range secrets {
 		if s.Name == selected {
-			fmt.Println()
+			fmt.Fprintln(out)
 			showSecretDetails(s)
 			break
 		}
@@ -140,76 +681,276 @@ func runInteractiveList(secrets []ssm.SecretMetadata) error {
 }
 
 func showSecretDetails(s ssm.SecretMetadata) {
-	fmt.Println(ui.SectionHeader("Selected"))
-	fmt.Println(ui.Highlight(s.Name))
-	fmt.Println()
+	fmt.Fprintln(out, ui.SectionHeader("Selected"))
+	fmt.Fprintln(out, ui.Highlight(s.Name))
+	fmt.Fprintln(out)
 
-	fmt.Println(ui.Subtle("Details:"))
-	fmt.Println("  Type:     " + s.Type)
-	fmt.Println("  Version:  " + fmt.Sprintf("%d", s.Version))
+	fmt.Fprintln(out, ui.Subtle("Details:"))
+	fmt.Fprintln(out, "  Type:     "+s.Type)
+	fmt.Fprintln(out, "  Version:  "+fmt.Sprintf("%d", s.Version))
 	if s.LastModified != nil {
-		fmt.Println("  Modified: " + s.LastModified.Local().Format("2006-01-02 15:04:05"))
+		fmt.Fprintln(out, "  Modified: "+s.LastModified.Local().Format("2006-01-02 15:04:05"))
+	}
+	if s.LastModifiedUser != "" {
+		fmt.Fprintln(out, "  By:       "+s.LastModifiedUser)
 	}
 
-	fmt.Println()
-	fmt.Println(ui.Subtle("To read the value:"))
-	fmt.Println("  lockr read " + s.Name)
-	fmt.Println()
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.Subtle("To read the value:"))
+	fmt.Fprintln(out, "  lockr read "+s.Name)
+	fmt.Fprintln(out)
+}
+
+// printListTSV writes the listing as tab-separated values with full names,
+// RFC 3339 times and untruncated tags
+func printListTSV(secrets []ssm.SecretMetadata) {
+	headers := []string{"name", "type", "version", "last_modified"}
+	if listModifiedBy != "" {
+		headers = append(headers, "last_modified_user")
+	}
+	if listWithKey || listGroupByKey {
+		headers = append(headers, "kms_key_id")
+	}
+	if listWithTags {
+		headers = append(headers, "tags")
+	}
+	if listWithValue {
+		headers = append(headers, "value")
+	}
+
+	rows := make([][]string, 0, len(secrets))
+	for _, s := range secrets {
+		lastMod := ""
+		if s.LastModified != nil {
+			lastMod = s.LastModified.UTC().Format(time.RFC3339)
+		}
+		row := []string{s.Name, s.Type, fmt.Sprintf("%d", s.Version), lastMod}
+		if listModifiedBy != "" {
+			row = append(row, s.LastModifiedUser)
+		}
+		if listWithKey || listGroupByKey {
+			row = append(row, s.KeyID)
+		}
+		if listWithTags {
+			pairs := make([]string, 0, len(s.Tags))
+			for _, kv := range sortedKeyValueRows(s.Tags) {
+				pairs = append(pairs, kv[0]+"="+kv[1])
+			}
+			row = append(row, strings.Join(pairs, ","))
+		}
+		if listWithValue {
+			row = append(row, s.Value)
+		}
+		rows = append(rows, row)
+	}
+	printTSV(headers, rows)
+}
+
+// awsManagedSSMKey is the default, AWS managed KMS key for SecureStrings
+const awsManagedSSMKey = "alias/aws/ssm"
+
+// defaultKeyMatcher returns a matcher for KMS key IDs that refer to the AWS
+// managed key: its alias or alias ARN, or its key ID or ARN, which
+// DescribeParameters may report instead. The alias is resolved with
+// DescribeKey; if that's denied, only the alias forms are matched, with a
+// warning.
+func defaultKeyMatcher(client *ssm.Client) (func(string) bool, error) {
+	byAlias := func(keyID string) bool {
+		return keyID == awsManagedSSMKey || strings.HasSuffix(keyID, ":"+awsManagedSSMKey)
+	}
+
+	key, err := client.ResolveKMSKey(context.Background(), awsManagedSSMKey)
+	if err != nil {
+		if !ssm.IsAccessDenied(err) {
+			return nil, fmt.Errorf("failed to resolve %s: %w", awsManagedSSMKey, err)
+		}
+		fmt.Fprintln(statusOut, ui.Warningf("Could not resolve %s (%v); matching secrets by alias only", awsManagedSSMKey, err))
+		return byAlias, nil
+	}
+	return func(keyID string) bool {
+		return byAlias(keyID) || key.Matches(keyID)
+	}, nil
+}
+
+// kmsKeyForms returns key and, if it can be resolved, the key's ARN, since
+// SSM's KeyId filter compares against whichever form it stored (the ARN for
+// customer managed keys). Resolution is best-effort.
+func kmsKeyForms(client *ssm.Client, key string) []string {
+	forms := []string{key}
+	resolved, err := client.ResolveKMSKey(context.Background(), key)
+	if err != nil {
+		fmt.Fprintln(statusOut, ui.Warningf("Could not resolve %s (%v); matching it as given", key, err))
+		return forms
+	}
+	for _, f := range []string{resolved.ARN, resolved.KeyID} {
+		if f != "" && !slices.Contains(forms, f) {
+			forms = append(forms, f)
+		}
+	}
+	return forms
+}
+
+// filterDefaultKey keeps the SecureStrings whose KMS key matches
+func filterDefaultKey(secrets []ssm.SecretMetadata, match func(string) bool) []ssm.SecretMetadata {
+	var filtered []ssm.SecretMetadata
+	for _, s := range secrets {
+		if s.Type == ssm.SecureStringType && match(s.KeyID) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// noKeyGroup is the --group-by-key group for parameters without a KMS key
+// (String and StringList)
+const noKeyGroup = "(none)"
+
+// groupByKey groups secrets by KMS key
+func groupByKey(secrets []ssm.SecretMetadata) map[string][]ssm.SecretMetadata {
+	groups := make(map[string][]ssm.SecretMetadata)
+	for _, s := range secrets {
+		key := s.KeyID
+		if key == "" {
+			key = noKeyGroup
+		}
+		groups[key] = append(groups[key], s)
+	}
+	return groups
+}
+
+// runKeyGroupedList shows one table per KMS key and warns about secrets that
+// use the AWS managed key
+func runKeyGroupedList(secrets []ssm.SecretMetadata) error {
+	groups := groupByKey(secrets)
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		title := "KMS key: " + key
+		switch key {
+		case awsManagedSSMKey:
+			title += " (AWS managed)"
+		case noKeyGroup:
+			title = "No KMS key (String/StringList)"
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader(title))
+		fmt.Fprintln(out)
+		rows := make([][]string, 0, len(groups[key]))
+		for _, s := range groups[key] {
+			rows = append(rows, []string{ui.Highlight(s.Name), s.Type, fmt.Sprintf("%d", s.Version)})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Name", "Type", "Version"}, rows))
+	}
+	fmt.Fprintln(out)
+
+	if n := len(groups[awsManagedSSMKey]); n > 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("%d secret(s) use the AWS managed key %s", n, awsManagedSSMKey))
+		fmt.Fprintln(statusOut)
+	}
+	return nil
 }
 
 func runTableList(secrets []ssm.SecretMetadata, basePath string) error {
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	title := "All Secrets"
 	if basePath != "/" {
 		title = fmt.Sprintf("Secrets at %s", basePath)
 	}
-	fmt.Println(ui.SectionHeader(title))
-	fmt.Println()
+	fmt.Fprintln(out, ui.SectionHeader(title))
+	fmt.Fprintln(out)
 
 	headers := []string{"Name", "Type", "Version", "Last Modified"}
+	if listModifiedBy != "" {
+		headers = append(headers, "Modified By")
+	}
+	if listWithKey {
+		headers = append(headers, "KMS Key")
+	}
+	if listWithTags {
+		headers = append(headers, "Tags")
+	}
+	if listWithValue {
+		headers = append(headers, "Value")
+	}
 	rows := make([][]string, 0, len(secrets))
 
 	for _, s := range secrets {
 		// Show relative path if it starts with the search path
-		displayName := s.Name
-		if basePath != "/" && strings.HasPrefix(s.Name, basePath) {
-			displayName = strings.TrimPrefix(s.Name, basePath)
-			if displayName == "" {
-				displayName = s.Name
-			} else if strings.HasPrefix(displayName, "/") {
-				displayName = displayName[1:]
-			}
-		}
+		displayName := relativeName(s.Name, basePath)
 
 		lastMod := "-"
 		if s.LastModified != nil {
 			lastMod = timeAgo(*s.LastModified)
 		}
 
-		rows = append(rows, []string{
+		row := []string{
 			ui.Highlight(displayName),
 			s.Type,
 			fmt.Sprintf("%d", s.Version),
 			lastMod,
-		})
+		}
+		if listModifiedBy != "" {
+			row = append(row, s.LastModifiedUser)
+		}
+		if listWithKey {
+			row = append(row, s.KeyID)
+		}
+		if listWithTags {
+			row = append(row, formatTagsCell(s.Tags))
+		}
+		if listWithValue {
+			row = append(row, s.Value)
+		}
+		rows = append(rows, row)
 	}
 
-	fmt.Println(ui.Table(headers, rows))
+	fmt.Fprintln(out, ui.Table(headers, rows))
 
-	fmt.Println()
-	fmt.Println(ui.Infof("Total: %d secret(s)", len(secrets)))
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.Infof("Total: %d secret(s)", len(secrets)))
 
 	// Hint about interactive mode
 	if !listInteractive && len(secrets) > 10 {
-		fmt.Println()
-		fmt.Println(ui.Subtle("Tip: Use 'lockr list -i' for interactive fuzzy search"))
+		fmt.Fprintln(statusOut)
+		fmt.Fprintln(statusOut, ui.Subtle("Tip: Use 'lockr list -i' for interactive fuzzy search"))
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 
 	return nil
 }
 
+// filterModifiedBy keeps secrets whose last modified user contains principal
+// (case-insensitive)
+func filterModifiedBy(secrets []ssm.SecretMetadata, principal string) []ssm.SecretMetadata {
+	principal = strings.ToLower(principal)
+	filtered := make([]ssm.SecretMetadata, 0, len(secrets))
+	for _, s := range secrets {
+		if strings.Contains(strings.ToLower(s.LastModifiedUser), principal) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+// relativeName returns name relative to basePath, or name unchanged if it
+// isn't under basePath
+func relativeName(name, basePath string) string {
+	if basePath == "/" || !strings.HasPrefix(name, basePath) {
+		return name
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(name, basePath), "/")
+	if rel == "" {
+		return name
+	}
+	return rel
+}
+
 // timeAgo returns a human-readable time difference
 func timeAgo(t time.Time) string {
 	diff := time.Since(t)
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/devops-chris/lockr/internal/ssm"
+)
+
+// mergeJSONValue reads the JSON object stored at path and applies each
+// --merge-json assignment to it, returning the merged JSON. An assignment is
+// key=value (value stored as a string) or key:=json (value parsed as JSON,
+// e.g. port:=5432 or tags:='["a","b"]'). Keys are dotted paths into nested
+// objects; missing objects along the way are created.
+func mergeJSONValue(client *ssm.Client, path string, assignments []string) (string, error) {
+	current, err := client.ReadSecret(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	// decodeJSONValue explains exactly why a value isn't JSON; decode again
+	// with UseNumber so numbers are written back exactly as they were
+	if _, err := decodeJSONValue(current.Value); err != nil {
+		return "", fmt.Errorf("can't merge into %s: %w", path, err)
+	}
+	var doc interface{}
+	dec := json.NewDecoder(strings.NewReader(current.Value))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("can't merge into %s: %w", path, err)
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("can't merge into %s: value is JSON but not an object", path)
+	}
+
+	for _, a := range assignments {
+		key, value, err := parseMergeAssignment(a)
+		if err != nil {
+			return "", err
+		}
+		if err := setDotted(root, key, value); err != nil {
+			return "", fmt.Errorf("--merge-json %s: %w", a, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// Keep multi-line (pretty-printed) values pretty
+	if strings.Contains(strings.TrimSpace(current.Value), "\n") {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(root); err != nil {
+		return "", fmt.Errorf("failed to encode merged JSON: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// parseMergeAssignment splits key=value (a string) or key:=json
+func parseMergeAssignment(a string) (string, interface{}, error) {
+	eq := strings.Index(a, "=")
+	if eq <= 0 {
+		return "", nil, fmt.Errorf("invalid --merge-json %q: use key=value or key:=json", a)
+	}
+	key, raw := a[:eq], a[eq+1:]
+	if !strings.HasSuffix(key, ":") {
+		return key, raw, nil
+	}
+
+	key = strings.TrimSuffix(key, ":")
+	if key == "" {
+		return "", nil, fmt.Errorf("invalid --merge-json %q: use key=value or key:=json", a)
+	}
+	var value interface{}
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return "", nil, fmt.Errorf("invalid --merge-json %q: %s is not valid JSON (use key=value for a string)", a, raw)
+	}
+	return key, value, nil
+}
+
+// setDotted sets value at a dotted key ("db.password") in obj, creating
+// missing objects. An existing non-object along the path is an error rather
+// than being overwritten.
+func setDotted(obj map[string]interface{}, key string, value interface{}) error {
+	parts := strings.Split(key, ".")
+	for _, p := range parts {
+		if p == "" {
+			return fmt.Errorf("invalid key %q", key)
+		}
+	}
+
+	node := obj
+	for i, p := range parts[:len(parts)-1] {
+		child, ok := node[p]
+		if !ok {
+			next := make(map[string]interface{})
+			node[p] = next
+			node = next
+			continue
+		}
+		next, ok := child.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not an object", strings.Join(parts[:i+1], "."))
+		}
+		node = next
+	}
+	node[parts[len(parts)-1]] = value
+	return nil
+}
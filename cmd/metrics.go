@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/metrics"
+	"github.com/devops-chris/lockr/internal/ssm"
+)
+
+// emitMetrics publishes, when metrics are enabled, how many secrets command
+// wrote or deleted (succeeded) and how many of those operations failed.
+// Outcomes with a count of 0 aren't published, so a dry run, a cancelled
+// prompt or an unchanged value publishes nothing. It uses the command's own
+// client and is best-effort: failures are reported on stderr but never fail
+// the command.
+func emitMetrics(client *ssm.Client, command string, succeeded, failed int) {
+	if !cfg.EmitMetrics {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	emitter := metrics.NewEmitter(client.AWSConfig(), cfg.MetricsNamespace)
+	for _, m := range []struct {
+		outcome string
+		count   int
+	}{{"success", succeeded}, {"failure", failed}} {
+		if m.count == 0 {
+			continue
+		}
+		if err := emitter.Emit(ctx, command, m.outcome, m.count); err != nil {
+			fmt.Fprintln(os.Stderr, ui.Warningf("Failed to emit metric: %v", err))
+			return
+		}
+	}
+}
+
+// emitOutcome publishes the metric for a command that writes one secret:
+// a failure if err is set, a success otherwise
+func emitOutcome(client *ssm.Client, command string, err error) {
+	if err != nil {
+		emitMetrics(client, command, 0, 1)
+		return
+	}
+	emitMetrics(client, command, 1, 0)
+}
+
+// resultCounts counts the writes in results that succeeded and failed.
+// Unchanged and already existing secrets weren't written, so they count as
+// neither.
+func resultCounts(results []writeResult) (succeeded, failed int) {
+	for _, r := range results {
+		switch r.Status {
+		case "failed":
+			failed++
+		case "unchanged", "exists":
+		default:
+			succeeded++
+		}
+	}
+	return succeeded, failed
+}
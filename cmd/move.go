@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveCarryHistory int
+	moveForce        bool
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move <source> <destination>",
+	Short: "Move (rename) a secret",
+	Long: `Move a secret to a new path: write it to the destination with the same
+type, tier, KMS key, description, tags and policies, then delete the source.
+
+SSM can't rename parameters, so version history is NOT portable: the
+destination starts again at version 1. With --carry-history N, the last N
+versions are replayed to the destination in order so some history is
+recreated - but version numbers, dates and authors will differ.
+
+Before the source is deleted, its tags are read again and checked against
+the destination's. If that or any earlier step fails, the destination is
+removed again and the source is left intact.
+
+You're asked to type the source path (or the confirm_word setting) to
+confirm; --force skips this.
+
+Examples:
+  lockr move /myapp/prod/old-name /myapp/prod/new-name
+
+  # Recreate the last 5 versions at the destination
+  lockr move /myapp/prod/old-name /myapp/prod/new-name --carry-history 5`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.Flags().IntVar(&moveCarryHistory, "carry-history", 0, "replay the last N versions to the destination (best-effort)")
+	moveCmd.Flags().BoolVarP(&moveForce, "force", "f", false, "skip confirmation prompt")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	src, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	dst, err := buildPath(args[1])
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("source and destination are the same")
+	}
+	if moveCarryHistory < 0 {
+		return fmt.Errorf("--carry-history must not be negative")
+	}
+
+	if !skipConfirm(moveForce) {
+		fmt.Println()
+		fmt.Println(ui.Warningf("You are about to move %s to %s", ui.Highlight(src), ui.Highlight(dst)))
+		fmt.Println(ui.Subtle("Version history is not preserved (see --carry-history)."))
+		fmt.Println()
+
+		confirmed, err := confirmTyped("move this secret", src, nil)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println(ui.Info("Cancelled"))
+			return nil
+		}
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var carried int
+	var moveErr error
+	_ = spinner.New().
+		Title("Moving secret...").
+		Action(func() {
+			carried, moveErr = moveSecret(client, src, dst, moveCarryHistory)
+		}).
+		Run()
+	emitOutcome(client, "move", moveErr)
+
+	if moveErr != nil {
+		fmt.Println(ui.Error("Failed to move secret"))
+		return fmt.Errorf("failed to move secret: %w", moveErr)
+	}
+
+	fmt.Println(ui.Successf("Moved %s → %s", src, dst))
+	if carried > 1 {
+		fmt.Println(ui.Subtle(fmt.Sprintf("Replayed %d versions", carried)))
+	}
+	fmt.Println()
+	return nil
+}
+
+// moveSecret copies src (and optionally its recent history) to dst, then
+// deletes src. The destination keeps src's type, tier, KMS key, description,
+// tags and policies. It returns how many versions were written. On failure
+// before the source is deleted, dst is removed and src is untouched.
+func moveSecret(client *ssm.Client, src, dst string, carryHistory int) (int, error) {
+	exists, err := client.Exists(dst)
+	if err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, fmt.Errorf("destination %s already exists", dst)
+	}
+
+	var history []string
+	if carryHistory > 1 {
+		versions, err := client.History(src, true)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read history: %w", err)
+		}
+		if len(versions) > carryHistory {
+			versions = versions[len(versions)-carryHistory:]
+		}
+		for _, v := range versions {
+			history = append(history, v.Value)
+		}
+	}
+
+	// The copy creates the destination, starting from the oldest carried
+	// version if there is history; later versions are written over it
+	opts := ssm.CopyOptions{Policies: true}
+	if len(history) > 0 {
+		opts.Placeholder = history[0]
+	}
+	if err := client.CopySecret(src, dst, opts); err != nil {
+		// Don't remove a destination someone else created meanwhile
+		if !ssm.IsAlreadyExists(err) {
+			rollbackMove(client, dst)
+		}
+		return 0, fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	written := 1
+	for i := 1; i < len(history); i++ {
+		if err := client.UpdateValue(dst, history[i]); err != nil {
+			rollbackMove(client, dst)
+			return 0, fmt.Errorf("failed to write %s: %w", dst, err)
+		}
+		written++
+	}
+
+	// The source's tags exist nowhere else once it's deleted, so check they
+	// made it across first
+	if err := checkTagsCopied(client, src, dst); err != nil {
+		rollbackMove(client, dst)
+		return 0, err
+	}
+
+	if err := client.DeleteSecret(src); err != nil {
+		rollbackMove(client, dst)
+		return 0, fmt.Errorf("failed to delete source %s: %w", src, err)
+	}
+
+	return written, nil
+}
+
+// checkTagsCopied returns an error unless dst carries every tag on src
+func checkTagsCopied(client *ssm.Client, src, dst string) error {
+	srcTags, err := client.GetTags(src)
+	if err != nil {
+		return fmt.Errorf("failed to read tags of %s: %w", src, err)
+	}
+	dstTags, err := client.GetTags(dst)
+	if err != nil {
+		return fmt.Errorf("failed to read tags of %s: %w", dst, err)
+	}
+	for k, v := range srcTags {
+		if got, ok := dstTags[k]; !ok || got != v {
+			return fmt.Errorf("tag %s was not copied to %s", k, dst)
+		}
+	}
+	return nil
+}
+
+// rollbackMove removes a partially written destination (best-effort)
+func rollbackMove(client *ssm.Client, dst string) {
+	_ = client.DeleteSecret(dst)
+}
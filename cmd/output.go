@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// out receives a command's primary output (JSON, tables, values). It is
+	// redirected to a file by --out-file.
+	out io.Writer = os.Stdout
+
+	// statusOut receives status messages. It moves to stderr when --out-file
+	// is set so the file only contains data.
+	statusOut io.Writer = os.Stdout
+
+	outFile    string
+	outFileRef *os.File
+)
+
+// openOutput redirects primary output to --out-file, if set. The file is
+// created (or truncated) with 0600 permissions since it may contain secrets.
+func openOutput() error {
+	if outFile == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(outFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open output file: %w", err)
+	}
+	// OpenFile doesn't change the mode of an existing file
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to set output file permissions: %w", err)
+	}
+
+	outFileRef = f
+	out = f
+	statusOut = os.Stderr
+	return nil
+}
+
+// closeOutput flushes and closes --out-file, if one was opened
+func closeOutput() error {
+	if outFileRef == nil {
+		return nil
+	}
+	if err := outFileRef.Close(); err != nil {
+		return fmt.Errorf("failed to close output file: %w", err)
+	}
+	outFileRef = nil
+	return nil
+}
+
+// redactedValue replaces secret values in output with --redact
+const redactedValue = "***"
+
+// shownValue returns v as it may be shown: masked with --redact
+func shownValue(v string) string {
+	if cfg.Redact {
+		return redactedValue
+	}
+	return v
+}
+
+// nestPaths turns a map keyed by slash-separated paths into nested objects,
+// e.g. {"/myapp/db/password": v} into {"myapp": {"db": {"password": v}}}.
+// A path that is both a value and a prefix of another path can't be
+// represented and is an error.
+func nestPaths(flat map[string]interface{}) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := make(map[string]interface{})
+	for _, key := range keys {
+		var segments []string
+		for _, seg := range strings.Split(key, "/") {
+			if seg != "" {
+				segments = append(segments, seg)
+			}
+		}
+		if len(segments) == 0 {
+			return nil, fmt.Errorf("can't nest %q: empty path", key)
+		}
+
+		node := root
+		for i, seg := range segments[:len(segments)-1] {
+			child, ok := node[seg]
+			if !ok {
+				next := make(map[string]interface{})
+				node[seg] = next
+				node = next
+				continue
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("can't nest %s: /%s is a secret and also a prefix of it", key, strings.Join(segments[:i+1], "/"))
+			}
+			node = next
+		}
+
+		leaf := segments[len(segments)-1]
+		if _, ok := node[leaf]; ok {
+			return nil, fmt.Errorf("can't nest %s: it is a secret and also a prefix of other secrets", key)
+		}
+		node[leaf] = flat[key]
+	}
+	return root, nil
+}
+
+// jsonFieldNames returns the JSON names of the exported fields of struct
+// type t, in declaration order
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseFields splits a --fields list and checks every name is one of valid
+func parseFields(list string, valid []string) ([]string, error) {
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(valid, f) {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", f, strings.Join(valid, ", "))
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields is empty (available: %s)", strings.Join(valid, ", "))
+	}
+	return fields, nil
+}
+
+// selectFields returns v's JSON object form with only fields. Fields v
+// omits (omitempty) are included as null so every record has the same shape.
+func selectFields(v interface{}, fields []string) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("failed to select fields: %w", err)
+	}
+
+	selected := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		selected[f] = all[f]
+	}
+	return selected, nil
+}
+
+// printTSV writes a header row and rows as tab-separated values, with no
+// color or box drawing, for pasting into spreadsheets and tickets. Tabs and
+// newlines inside fields are escaped as \t and \n so every row stays on one
+// line.
+func printTSV(headers []string, rows [][]string) {
+	escape := strings.NewReplacer("\\", "\\\\", "\t", `\t`, "\r", `\r`, "\n", `\n`)
+	writeRow := func(fields []string) {
+		escaped := make([]string, len(fields))
+		for i, f := range fields {
+			escaped[i] = escape.Replace(f)
+		}
+		fmt.Fprintln(out, strings.Join(escaped, "\t"))
+	}
+
+	writeRow(headers)
+	for _, row := range rows {
+		writeRow(row)
+	}
+}
+
+// printCSV writes a header row and rows as RFC 4180 CSV, quoting fields
+// that contain commas, quotes or newlines
+func printCSV(headers []string, rows [][]string) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// jsonArrayWriter writes a JSON array to out one element at a time, formatted
+// like printStructured's output, so long lists needn't be held in memory.
+// Nothing is written until the first element; call Close to end the array,
+// and only on success, so an interrupted array doesn't parse.
+type jsonArrayWriter struct {
+	count int
+}
+
+// Write adds v to the array
+func (w *jsonArrayWriter) Write(v interface{}) error {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	sep := ",\n  "
+	if w.count == 0 {
+		sep = "[\n  "
+	}
+	w.count++
+	_, err = fmt.Fprint(out, sep+string(data))
+	return err
+}
+
+// Close ends the array, if anything was written
+func (w *jsonArrayWriter) Close() error {
+	if w.count == 0 {
+		return nil
+	}
+	_, err := fmt.Fprint(out, "\n]\n")
+	return err
+}
+
+// printStructured writes v to out as indented JSON, or as YAML with
+// --output yaml. The YAML uses the same keys as the JSON (map keys sorted,
+// struct fields in declaration order) in block style with multi-line values
+// as literal blocks, so committed output diffs cleanly.
+func printStructured(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	if cfg.Output != "yaml" {
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	// Round-trip through a yaml.Node so key order and names match the JSON
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert to YAML: %w", err)
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// blockStyle clears the flow/quoting styles a node picked up from JSON so the
+// encoder chooses block style and only quotes where YAML requires it
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+)
+
+const (
+	pathBuilderHere = "\x00here"
+	pathBuilderUp   = "\x00up"
+)
+
+// interactivePathBuilder helps construct a new secret path by drilling down
+// through the existing path segments, then typing the final name. It returns
+// "" if the user cancels.
+func interactivePathBuilder() (string, error) {
+	base := pathBase()
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return "", fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var names []string
+	var listErr error
+	_ = spinner.New().
+		Title("Fetching existing paths...").
+		Action(func() {
+			secrets, err := client.ListSecrets(apiPath(base), true)
+			if err != nil {
+				listErr = err
+				return
+			}
+			for _, s := range secrets {
+				names = append(names, s.Name)
+			}
+		}).
+		Run()
+
+	if listErr != nil {
+		fmt.Println(ui.Error("Failed to list secrets"))
+		return "", fmt.Errorf("failed to list secrets: %w", listErr)
+	}
+
+	current := base
+	for {
+		dirs, leaves := pathChildren(names, current)
+
+		opts := []huh.Option[string]{huh.NewOption("✎ Name the secret here", pathBuilderHere)}
+		if current != base {
+			opts = append(opts, huh.NewOption("↑ Up one level", pathBuilderUp))
+		}
+		for _, d := range dirs {
+			opts = append(opts, huh.NewOption(d+"/", d))
+		}
+
+		desc := "No existing secrets here"
+		if len(leaves) > 0 {
+			desc = "Existing: " + strings.Join(leaves, ", ")
+		}
+
+		var choice string
+		sel := huh.NewSelect[string]().
+			Title("Path: " + current).
+			Description(desc).
+			Options(opts...).
+			Value(&choice)
+		sel.WithTheme(ui.Theme())
+		if err := sel.Run(); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return "", nil
+			}
+			return "", err
+		}
+
+		switch choice {
+		case pathBuilderUp:
+			current = parentPath(current)
+		case pathBuilderHere:
+			return promptLeafName(current, leaves)
+		default:
+			current += choice + "/"
+		}
+	}
+}
+
+// promptLeafName asks for the final name (which may contain further
+// segments) under dir
+func promptLeafName(dir string, existing []string) (string, error) {
+	var name string
+	input := huh.NewInput().
+		Title("Secret name").
+		Description("Under " + dir).
+		Value(&name).
+		Validate(func(s string) error {
+			s = strings.Trim(strings.TrimSpace(s), "/")
+			if s == "" {
+				return fmt.Errorf("name cannot be empty")
+			}
+			for _, e := range existing {
+				if e == s {
+					return fmt.Errorf("%s%s already exists", dir, s)
+				}
+			}
+			return nil
+		})
+	input.WithTheme(ui.Theme())
+
+	if err := input.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return dir + strings.Trim(strings.TrimSpace(name), "/"), nil
+}
+
+// pathChildren returns the distinct sub-directories and the secret names
+// directly under dir (which must end in "/"), both sorted
+func pathChildren(names []string, dir string) (dirs, leaves []string) {
+	seen := make(map[string]bool)
+	for _, n := range names {
+		if !strings.HasPrefix(n, dir) {
+			continue
+		}
+		rest := strings.TrimPrefix(n, dir)
+		if i := strings.Index(rest, "/"); i > 0 {
+			if d := rest[:i]; !seen[d] {
+				seen[d] = true
+				dirs = append(dirs, d)
+			}
+		} else if rest != "" {
+			leaves = append(leaves, rest)
+		}
+	}
+	sort.Strings(dirs)
+	sort.Strings(leaves)
+	return dirs, leaves
+}
+
+// parentPath returns the parent of dir, e.g. /a/b/ -> /a/
+func parentPath(dir string) string {
+	trimmed := strings.TrimSuffix(dir, "/")
+	i := strings.LastIndex(trimmed, "/")
+	if i < 0 {
+		return "/"
+	}
+	return trimmed[:i+1]
+}
+
+// apiPath converts a directory path ending in "/" to the form the SSM API
+// expects (no trailing slash, except for the root)
+func apiPath(dir string) string {
+	if dir == "/" {
+		return dir
+	}
+	return strings.TrimSuffix(dir, "/")
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"atomicgo.dev/cursor"
@@ -14,11 +15,55 @@ import (
 
 // pickItem is one row in the interactive picker. display is the (possibly
 // styled) string shown to the user; search is the plain text the filter runs
-// against; value is what the picker returns when the row is chosen.
+// against; value is what the picker returns when the row is chosen. Items with
+// a group are shown under a header line whenever the group changes.
 type pickItem struct {
 	display string
 	search  string
 	value   string
+	group   string
+}
+
+// pickerGroup controls how secret names are ordered in the picker: "none"
+// (API order), "alpha" (sorted) or "prefix" (sorted, grouped by top-level
+// path segment)
+var pickerGroup string
+
+// validatePickerGroup checks the --group flag value
+func validatePickerGroup() error {
+	switch pickerGroup {
+	case "none", "alpha", "prefix":
+		return nil
+	default:
+		return fmt.Errorf("invalid --group %q (use none, alpha or prefix)", pickerGroup)
+	}
+}
+
+// secretPickItems builds picker rows for secret names, ordered according to
+// --group. The value of each row is always the full path.
+func secretPickItems(names []string) []pickItem {
+	if pickerGroup != "none" {
+		names = append([]string(nil), names...)
+		sort.Strings(names)
+	}
+
+	items := make([]pickItem, len(names))
+	for i, name := range names {
+		items[i] = pickItem{display: name, search: name, value: name}
+		if pickerGroup == "prefix" {
+			items[i].group = topSegment(name)
+		}
+	}
+	return items
+}
+
+// topSegment returns the first segment of a path, e.g. /myapp/prod/key -> myapp
+func topSegment(name string) string {
+	trimmed := strings.TrimPrefix(name, "/")
+	if i := strings.Index(trimmed, "/"); i >= 0 {
+		return trimmed[:i]
+	}
+	return "/"
 }
 
 var (
@@ -103,6 +148,9 @@ func runPicker(items []pickItem, maxHeight int) (value string, ok bool) {
 		start, end := windowBounds(len(matched), maxHeight, cur)
 		for i := start; i < end; i++ {
 			it := items[matched[i]]
+			if it.group != "" && (i == start || items[matched[i-1]].group != it.group) {
+				b.WriteString(pickerSearchStyle.Render("── "+it.group+" ──") + "\n")
+			}
 			if i == cur {
 				b.WriteString(pickerCursorStyle.Render("❯ ") + it.display + "\n")
 			} else {
@@ -0,0 +1,344 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var (
+	policiesRecursive bool
+
+	policyExpires        string
+	policyNotifyBefore   string
+	policyNotifyNoChange string
+)
+
+var policiesCmd = &cobra.Command{
+	Use:   "policies <path>",
+	Short: "Show the parameter policies of a secret",
+	Long: `Show the parameter policies (Expiration, ExpirationNotification,
+NoChangeNotification) attached to a secret. Only Advanced-tier parameters
+can have policies.
+
+With --recursive, shows every secret under the path that has policies, to
+audit which secrets will expire and when.
+
+Examples:
+  lockr policies /myapp/prod/api-key
+  lockr policies /myapp --recursive
+  lockr policies /myapp --recursive --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPolicies,
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Set or clear the parameter policies of a secret",
+	Long: `Set or clear the parameter policies of an existing secret.
+
+SSM can only change policies by writing the parameter, so the current value
+is written back unchanged: the secret gets a new version, keeping its type,
+KMS key and description. Setting policies moves the secret to the Advanced
+tier (which has a per-parameter cost and can't be undone without deleting
+the parameter). Use lockr policies to view them.
+
+Durations are a number with a d (days) or h (hours) suffix.
+
+Examples:
+  # Expire in 30 days, notify 7 days before
+  lockr policy set /myapp/prod/api-key --expires 30d --notify-before 7d
+
+  # Expire at a fixed time
+  lockr policy set /myapp/prod/api-key --expires 2026-12-31T00:00:00Z
+
+  # Notify if the secret hasn't changed for 90 days
+  lockr policy set /myapp/prod/api-key --notify-no-change 90d
+
+  # Remove all policies
+  lockr policy clear /myapp/prod/api-key`,
+}
+
+var policySetCmd = &cobra.Command{
+	Use:   "set <path>",
+	Short: "Replace the policies of a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolicySet,
+}
+
+var policyClearCmd = &cobra.Command{
+	Use:   "clear <path>",
+	Short: "Remove all policies from a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPolicyClear,
+}
+
+func init() {
+	rootCmd.AddCommand(policiesCmd, policyCmd)
+	policyCmd.AddCommand(policySetCmd, policyClearCmd)
+
+	policiesCmd.Flags().BoolVarP(&policiesRecursive, "recursive", "r", false, "show the policies of every secret under the path")
+
+	policySetCmd.Flags().StringVar(&policyExpires, "expires", "", "delete the secret after this duration (e.g. 30d, 12h) or at an RFC 3339 time")
+	policySetCmd.Flags().StringVar(&policyNotifyBefore, "notify-before", "", "send an EventBridge notification this long before expiration (e.g. 7d)")
+	policySetCmd.Flags().StringVar(&policyNotifyNoChange, "notify-no-change", "", "send an EventBridge notification if unchanged for this long (e.g. 90d)")
+}
+
+func runPolicies(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var secrets []ssm.SecretMetadata
+	var descErr error
+	_ = spinner.New().
+		Title("Fetching policies...").
+		Action(func() {
+			if policiesRecursive {
+				secrets, descErr = client.DescribeSecrets(path, true)
+				return
+			}
+			var meta *ssm.SecretMetadata
+			meta, descErr = client.DescribeSecret(path)
+			if descErr == nil {
+				secrets = []ssm.SecretMetadata{*meta}
+			}
+		}).
+		Run()
+
+	if descErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to fetch policies"))
+		return fmt.Errorf("failed to fetch policies: %w", descErr)
+	}
+
+	type secretPolicies struct {
+		Name     string       `json:"name"`
+		Tier     string       `json:"tier"`
+		Policies []ssm.Policy `json:"policies"`
+	}
+	var withPolicies []secretPolicies
+	for _, s := range secrets {
+		if len(s.Policies) > 0 {
+			withPolicies = append(withPolicies, secretPolicies{Name: s.Name, Tier: s.Tier, Policies: s.Policies})
+		}
+	}
+	sort.Slice(withPolicies, func(i, j int) bool { return withPolicies[i].Name < withPolicies[j].Name })
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if withPolicies == nil {
+			withPolicies = []secretPolicies{}
+		}
+		return printStructured(withPolicies)
+	}
+
+	if len(withPolicies) == 0 {
+		if policiesRecursive {
+			fmt.Fprintln(statusOut, ui.Infof("No secrets under %s have policies", path))
+		} else {
+			fmt.Fprintln(statusOut, ui.Infof("%s has no policies (%s tier)", path, secrets[0].Tier))
+		}
+		return nil
+	}
+
+	var rows [][]string
+	for _, s := range withPolicies {
+		for _, p := range s.Policies {
+			row := []string{p.Type, p.Status, policyDetails(p)}
+			if policiesRecursive {
+				row = append([]string{s.Name}, row...)
+			}
+			rows = append(rows, row)
+		}
+	}
+	headers := []string{"Policy", "Status", "Parameters"}
+	if policiesRecursive {
+		headers = append([]string{"Name"}, headers...)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.Table(headers, rows))
+	fmt.Fprintln(out)
+	return nil
+}
+
+// policyDetails renders a policy's attributes as "Key=value, ..." sorted by
+// key, or the raw policy text if it couldn't be parsed
+func policyDetails(p ssm.Policy) string {
+	if p.Text != "" {
+		return p.Text
+	}
+	keys := make([]string, 0, len(p.Attributes))
+	for k := range p.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + p.Attributes[k]
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runPolicySet(cmd *cobra.Command, args []string) error {
+	if policyExpires == "" && policyNotifyBefore == "" && policyNotifyNoChange == "" {
+		return fmt.Errorf("give at least one of --expires, --notify-before or --notify-no-change")
+	}
+	if policyNotifyBefore != "" && policyExpires == "" {
+		return fmt.Errorf("--notify-before requires --expires")
+	}
+
+	policies, err := buildPolicies(time.Now())
+	if err != nil {
+		return err
+	}
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	return updatePolicies(path, policies, "Policies set")
+}
+
+func runPolicyClear(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	return updatePolicies(path, "[]", "Policies cleared")
+}
+
+// updatePolicies writes policies (a JSON array) to path
+func updatePolicies(path, policies, done string) error {
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var setErr error
+	_ = spinner.New().
+		Title("Updating policies...").
+		Action(func() {
+			setErr = client.SetPolicies(path, policies)
+		}).
+		Run()
+	emitOutcome(client, "policy", setErr)
+
+	if setErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to update policies"))
+		return fmt.Errorf("failed to update policies: %w", setErr)
+	}
+
+	if cfg.Output != "text" {
+		return printStructured(map[string]interface{}{"path": path, "policies": json.RawMessage(policies)})
+	}
+	fmt.Println(ui.Successf("%s: %s", done, path))
+	return nil
+}
+
+// policyDoc is the JSON form of a parameter policy as PutParameter expects it
+type policyDoc struct {
+	Type       string            `json:"Type"`
+	Version    string            `json:"Version"`
+	Attributes map[string]string `json:"Attributes"`
+}
+
+// buildPolicies turns the policy set flags into a JSON policy array, with
+// relative expirations counted from now
+func buildPolicies(now time.Time) (string, error) {
+	var docs []policyDoc
+
+	if policyExpires != "" {
+		at, err := time.Parse(time.RFC3339, policyExpires)
+		if err != nil {
+			d, perr := parseDayDuration(policyExpires)
+			if perr != nil {
+				return "", fmt.Errorf("invalid --expires %q: use a duration like 30d or an RFC 3339 time", policyExpires)
+			}
+			at = now.Add(d)
+		}
+		if !at.After(now) {
+			return "", fmt.Errorf("--expires must be in the future")
+		}
+		docs = append(docs, policyDoc{
+			Type:       "Expiration",
+			Version:    "1.0",
+			Attributes: map[string]string{"Timestamp": at.UTC().Format(time.RFC3339)},
+		})
+	}
+
+	if policyNotifyBefore != "" {
+		n, unit, err := parsePolicyDuration(policyNotifyBefore)
+		if err != nil {
+			return "", fmt.Errorf("invalid --notify-before: %w", err)
+		}
+		docs = append(docs, policyDoc{
+			Type:       "ExpirationNotification",
+			Version:    "1.0",
+			Attributes: map[string]string{"Before": strconv.Itoa(n), "Unit": unit},
+		})
+	}
+
+	if policyNotifyNoChange != "" {
+		n, unit, err := parsePolicyDuration(policyNotifyNoChange)
+		if err != nil {
+			return "", fmt.Errorf("invalid --notify-no-change: %w", err)
+		}
+		docs = append(docs, policyDoc{
+			Type:       "NoChangeNotification",
+			Version:    "1.0",
+			Attributes: map[string]string{"After": strconv.Itoa(n), "Unit": unit},
+		})
+	}
+
+	data, err := json.Marshal(docs)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// parsePolicyDuration parses "30d" or "12h" into a count and the policy unit
+// ("Days" or "Hours")
+func parsePolicyDuration(s string) (int, string, error) {
+	units := map[string]string{"d": "Days", "h": "Hours"}
+	if len(s) < 2 {
+		return 0, "", fmt.Errorf("%q is not a duration like 30d or 12h", s)
+	}
+	unit, ok := units[s[len(s)-1:]]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if !ok || err != nil || n < 1 {
+		return 0, "", fmt.Errorf("%q is not a duration like 30d or 12h", s)
+	}
+	return n, unit, nil
+}
+
+// parseDayDuration parses "30d" or "12h" into a time.Duration
+func parseDayDuration(s string) (time.Duration, error) {
+	n, unit, err := parsePolicyDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(n) * time.Hour
+	if unit == "Days" {
+		d *= 24
+	}
+	return d, nil
+}
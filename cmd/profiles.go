@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	pathpkg "path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// resolvedPaths are the secret paths this run has resolved with buildPath.
+// Commands resolve their paths before creating a client, and newClient uses
+// them to pick an AWS profile from path_profile_map. clientProfile is the
+// profile the first client was created with (once clientCreated is set), so a
+// path resolved later that maps elsewhere is refused instead of being used
+// with the wrong account.
+var (
+	resolvedPaths   []string
+	clientProfile   string
+	clientCreated   bool
+	resolvedPathsMu sync.Mutex
+)
+
+// recordPath notes a path resolved by buildPath. It fails if a client already
+// exists for a different profile than the one path maps to.
+func recordPath(path string) error {
+	resolvedPathsMu.Lock()
+	defer resolvedPathsMu.Unlock()
+
+	if clientCreated && len(cfg.PathProfileMap) > 0 {
+		if p := profileForPath(path); p != clientProfile {
+			return fmt.Errorf("%s maps to the AWS profile %s in path_profile_map, but this command is already using %s",
+				path, profileName(p), profileName(clientProfile))
+		}
+	}
+	resolvedPaths = append(resolvedPaths, path)
+	return nil
+}
+
+// useProfile records the profile a client was created with
+func useProfile(profile string) {
+	resolvedPathsMu.Lock()
+	defer resolvedPathsMu.Unlock()
+	clientProfile, clientCreated = profile, true
+}
+
+// pathProfile returns the AWS profile that path_profile_map selects for the
+// paths resolved so far, or "" to use the default profile. All paths must map
+// to the same profile so one command never mixes accounts.
+func pathProfile() (string, error) {
+	if len(cfg.PathProfileMap) == 0 {
+		return "", nil
+	}
+
+	resolvedPathsMu.Lock()
+	defer resolvedPathsMu.Unlock()
+
+	profile, first := "", ""
+	for i, path := range resolvedPaths {
+		p := profileForPath(path)
+		if i == 0 {
+			profile, first = p, path
+			continue
+		}
+		if p != profile {
+			return "", fmt.Errorf("%s and %s map to different AWS profiles (%s, %s) in path_profile_map",
+				first, path, profileName(profile), profileName(p))
+		}
+	}
+	return profile, nil
+}
+
+// profileForPath returns the profile of the most specific path_profile_map
+// pattern matching path or one of its parent paths. Patterns are globs
+// (path.Match syntax) matched case-insensitively, since config keys are
+// lower-cased when loaded.
+func profileForPath(path string) string {
+	patterns := make([]string, 0, len(cfg.PathProfileMap))
+	for pattern := range cfg.PathProfileMap {
+		patterns = append(patterns, pattern)
+	}
+	// Longest pattern first, then alphabetical for a stable choice
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	lower := strings.ToLower(path)
+	for _, pattern := range patterns {
+		for p := lower; p != "/" && p != "."; p = pathpkg.Dir(p) {
+			if ok, _ := pathpkg.Match(strings.ToLower(pattern), p); ok {
+				return cfg.PathProfileMap[pattern]
+			}
+		}
+	}
+	return ""
+}
+
+func profileName(profile string) string {
+	if profile == "" {
+		return "default"
+	}
+	return profile
+}
@@ -1,16 +1,51 @@
 package cmd
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/devops-chris/clihq/ui"
 	"github.com/devops-chris/lockr/internal/ssm"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
-var readQuiet bool
+var (
+	readQuiet        bool
+	readDefault      string
+	readAll          bool
+	readMinVersion   int64
+	readRetryTimeout time.Duration
+	readEquals       string
+	readFingerprint  bool
+	readReveal       bool
+	readJSONPath     string
+	readCopy         bool
+	readClearAfter   time.Duration
+	readCertInfo     bool
+	readFull         bool
+	readNested       bool
+	readFields       string
+	readTable        bool
+)
+
+// readFieldNames are the fields of read's JSON/YAML output, for --fields
+var readFieldNames = []string{"name", "value", "type", "version", "tags", "default"}
+
+// readTableOverhead is the width the read table takes besides the value:
+// three borders, two cells' padding and the widest property ("Property")
+const readTableOverhead = 3 + 4 + len("Property")
 
 var readCmd = &cobra.Command{
 	Use:   "read [path]",
@@ -19,32 +54,218 @@ var readCmd = &cobra.Command{
 
 Without a path, opens interactive search to find and read a secret.
 
+With --copy, the value is put on the clipboard and never printed; only a
+confirmation is shown. --clear-after keeps lockr running for that long and
+then clears the clipboard (unless something else was copied meanwhile).
+Copying needs a clipboard: pbcopy on macOS, clip on Windows, and wl-copy,
+xclip or xsel with a running display on Linux, so it fails on headless
+machines and in CI.
+
+Values are opaque strings; only --jsonpath parses them as JSON, and a value
+that isn't valid JSON (trailing data, a byte order mark, a syntax error) fails
+with the exact reason and position.
+
+With --cert-info, a value holding PEM certificates (a certificate, a chain, or
+a certificate bundled with its key) is summarized instead of printed: subject,
+issuer, SANs, validity and fingerprint. Private keys are never shown. A
+certificate that has expired or expires within 30 days is warned about.
+
+In a terminal, long lines in the value (JWTs, base64 blobs) are cut to fit
+the table so it doesn't wrap; --full wraps them inside the table instead.
+--quiet and JSON/YAML output always contain the complete value.
+
+With --output raw-base64, only the base64 of the value's raw bytes is printed
+(one line). Values with control characters or non-printable bytes can then be
+copied without corrupting the terminal, and decoded exactly with base64 -d.
+
+A path with glob characters (* ? [ ]) reads every secret it matches, e.g.
+'/myapp/prod/*' (quote it so the shell doesn't expand it). Matching follows
+path.Match, so * doesn't cross a /: '/myapp/*/api-key' matches one level.
+The matches are found with a recursive list from the part before the first
+glob segment and read in batches. Several matches need --output json (a map
+of full name to value) or --table; a single match is shown as usual. A glob
+that matches nothing only warns, while a plain path that doesn't exist is
+still an error.
+
+With --all, reads every secret under the path (recursively) and outputs them
+as a single object keyed by path relative to the given path. Add
+--secure-only to skip plain String and StringList parameters, and --nested
+to split the paths into nested objects ({"db": {"password": "..."}}) for
+config loaders that expect hierarchical data.
+
 Examples:
   # Interactive search, then read
   lockr read
 
+  # Interactive search, sorted alphabetically
+  lockr read --group alpha
+
   # Read a specific secret
   lockr read /myapp/prod/api-key
 
   # Output as JSON
   lockr read /myapp/prod/api-key --output json
 
+  # The raw value as base64 (for values with control characters)
+  lockr read /myapp/prod/api-key --output raw-base64 | base64 -d
+
+  # Every secret matching a glob, as {"/myapp/prod/a": "...", ...} or a table
+  lockr read '/myapp/prod/*' --output json
+  lockr read '/myapp/*/api-key' --table
+
+  # Only some fields in JSON output
+  lockr read /myapp/prod/api-key --output json --fields name,version
+
   # Quiet mode (value only, for scripts)
-  lockr read /myapp/prod/api-key --quiet`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runRead,
+  lockr read /myapp/prod/api-key --quiet
+
+  # Fall back to a default if the secret doesn't exist
+  lockr read /myapp/prod/feature-flag --quiet --default "off"
+
+  # Retry until at least version 5 is visible (read-after-write in another region)
+  lockr read /myapp/prod/api-key --min-version 5 --retry-timeout 1m
+
+  # Health check: exit 0 if the value matches, 1 otherwise (value never printed)
+  lockr read /myapp/prod/api-key --equals "$EXPECTED" --quiet
+
+  # Extract from a JSON value with JSONPath (arrays and nesting supported)
+  lockr read /myapp/db --jsonpath '$.connections[0].password'
+
+  # Copy to the clipboard without showing it, and clear it after 30 seconds
+  lockr read /myapp/prod/api-key --copy --clear-after 30s
+
+  # Inspect a stored TLS certificate without printing it (or its key)
+  lockr read /myapp/prod/tls-cert --cert-info
+
+  # Length and SHA-256 fingerprint instead of the value (compare without revealing)
+  lockr read /myapp/prod/api-key --fingerprint
+
+  # Read a whole subtree as {"db/password": "...", "api/key": "..."}
+  lockr read /myapp/prod --all --output json
+
+  # Only SecureString secrets under the path
+  lockr read /myapp/prod --all --secure-only --output json
+
+  # The same as nested objects: {"db": {"password": "..."}, "api": {...}}
+  lockr read /myapp/prod --all --nested --output json`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{rawBase64Annotation: "true", outFileAnnotation: "true"},
+	RunE:        runRead,
 }
 
 func init() {
 	rootCmd.AddCommand(readCmd)
 	readCmd.Flags().BoolVarP(&readQuiet, "quiet", "q", false, "output value only (for scripts)")
+	readCmd.Flags().StringVar(&readDefault, "default", "", "value to output if the secret doesn't exist")
+	readCmd.Flags().Int64Var(&readMinVersion, "min-version", 0, "retry until the returned version is at least this (for stale replicas)")
+	readCmd.Flags().DurationVar(&readRetryTimeout, "retry-timeout", 30*time.Second, "how long --min-version keeps retrying")
+	readCmd.Flags().StringVar(&readEquals, "equals", "", "exit 0 if the value equals this, 1 otherwise (prints no value)")
+	readCmd.Flags().StringVar(&readJSONPath, "jsonpath", "", "print the result of a JSONPath expression evaluated against a JSON value")
+	readCmd.Flags().BoolVar(&readFingerprint, "fingerprint", false, "show the value's length and SHA-256 fingerprint instead of the value")
+	readCmd.Flags().BoolVar(&readFull, "full", false, "wrap long values inside the table instead of cutting them to the terminal width")
+	readCmd.Flags().BoolVar(&readCertInfo, "cert-info", false, "summarize the PEM certificate(s) in the value instead of printing it")
+	readCmd.Flags().BoolVar(&readReveal, "reveal", false, "with --fingerprint, also show the value")
+	readCmd.Flags().BoolVarP(&readCopy, "copy", "c", false, "copy the value to the clipboard instead of printing it")
+	readCmd.Flags().DurationVar(&readClearAfter, "clear-after", 0, "with --copy, clear the clipboard after this long (e.g. 30s)")
+	readCmd.Flags().BoolVar(&readAll, "all", false, "read every secret under the path as a map of relative path to value")
+	readCmd.Flags().StringVar(&readFields, "fields", "", "with --output json/yaml, only include these fields (comma-separated: "+strings.Join(readFieldNames, ", ")+")")
+	readCmd.Flags().BoolVar(&readTable, "table", false, "with a glob path, show the matching secrets as a table")
+	readCmd.Flags().BoolVar(&readNested, "nested", false, "with --all, output nested objects split on / instead of flat paths")
+	readCmd.Flags().BoolVar(&secureOnly, "secure-only", false, "with --all, only include SecureString parameters")
+	readCmd.Flags().StringVar(&pickerGroup, "group", "none", "interactive search order: none, alpha, or prefix (group by top-level segment)")
 }
 
 func runRead(cmd *cobra.Command, args []string) error {
+	if readCopy && (readAll || readFingerprint || readJSONPath != "" || cmd.Flags().Changed("equals")) {
+		return fmt.Errorf("--copy cannot be used with --all, --equals, --fingerprint or --jsonpath")
+	}
+	if readCertInfo && (readAll || readCopy || readFingerprint || readJSONPath != "" || cmd.Flags().Changed("equals")) {
+		return fmt.Errorf("--cert-info cannot be used with --all, --copy, --equals, --fingerprint or --jsonpath")
+	}
+	if readClearAfter != 0 && !readCopy {
+		return fmt.Errorf("--clear-after requires --copy")
+	}
+	if readClearAfter < 0 {
+		return fmt.Errorf("--clear-after must not be negative")
+	}
+	if cfg.Output == "raw-base64" && (readAll || readCopy || readFingerprint || readCertInfo || readJSONPath != "" || cmd.Flags().Changed("equals")) {
+		return fmt.Errorf("--output raw-base64 cannot be used with --all, --copy, --equals, --fingerprint, --cert-info or --jsonpath")
+	}
+	if cfg.Redact && (readQuiet || readJSONPath != "" || cfg.Output == "raw-base64") && !readCopy && !readFingerprint && !readCertInfo && !cmd.Flags().Changed("equals") {
+		return fmt.Errorf("--redact: refusing to print the value (--quiet, --jsonpath or --output raw-base64)")
+	}
+
+	var fields []string
+	if readFields != "" {
+		if cfg.Output != "json" && cfg.Output != "yaml" {
+			return fmt.Errorf("--fields requires --output json or yaml")
+		}
+		if readAll || readQuiet || readCopy || readFingerprint || readCertInfo || readJSONPath != "" || cmd.Flags().Changed("equals") {
+			return fmt.Errorf("--fields only applies to reading a single secret's JSON/YAML output")
+		}
+		var err error
+		if fields, err = parseFields(readFields, readFieldNames); err != nil {
+			return err
+		}
+	}
+
+	glob := len(args) > 0 && isGlob(args[0])
+	if readTable && (!glob || cfg.Output != "text") {
+		return fmt.Errorf("--table requires a glob path and text output")
+	}
+	if glob && (readAll || readCopy || readFingerprint || readCertInfo || readJSONPath != "" || readMinVersion > 0 || readFields != "" ||
+		cfg.Output == "raw-base64" || cmd.Flags().Changed("equals") || cmd.Flags().Changed("default")) {
+		return fmt.Errorf("a glob path cannot be used with --all, --cert-info, --copy, --default, --equals, --fields, --fingerprint, --jsonpath, --min-version or --output raw-base64")
+	}
+
+	if cmd.Flags().Changed("equals") {
+		if len(args) == 0 {
+			return fmt.Errorf("--equals requires a path")
+		}
+		if readAll || cmd.Flags().Changed("default") {
+			return fmt.Errorf("--equals cannot be used with --all or --default")
+		}
+		path, err := buildPath(args[0])
+		if err != nil {
+			return err
+		}
+		return runReadEquals(cmd, path)
+	}
+
+	if readAll {
+		if len(args) == 0 {
+			return fmt.Errorf("--all requires a path")
+		}
+		if cmd.Flags().Changed("default") {
+			return fmt.Errorf("--default cannot be used with --all")
+		}
+		if readMinVersion > 0 {
+			return fmt.Errorf("--min-version cannot be used with --all")
+		}
+		if readNested && !readQuiet && cfg.Output == "text" {
+			return fmt.Errorf("--nested requires --output json or yaml")
+		}
+		path, err := buildPath(args[0])
+		if err != nil {
+			return err
+		}
+		return runReadAll(path)
+	}
+	if secureOnly || readNested {
+		return fmt.Errorf("--secure-only and --nested require --all")
+	}
+
+	if readMinVersion > 0 && cmd.Flags().Changed("default") {
+		return fmt.Errorf("--default cannot be used with --min-version")
+	}
+
 	var path string
 
 	// If no path provided, do interactive search first
 	if len(args) == 0 {
+		if err := validatePickerGroup(); err != nil {
+			return err
+		}
 		selectedPath, err := interactiveSecretSearch()
 		if err != nil {
 			return err
@@ -52,30 +273,99 @@ func runRead(cmd *cobra.Command, args []string) error {
 		if selectedPath == "" {
 			return nil // User cancelled
 		}
+		if cfg.ConfirmReveal {
+			reveal, err := confirmReveal(selectedPath)
+			if err != nil {
+				return err
+			}
+			if !reveal {
+				fmt.Println(ui.Info("Cancelled"))
+				return nil
+			}
+		}
 		path = selectedPath
 	} else {
-		path = buildPath(args[0])
+		var err error
+		if path, err = buildPath(args[0]); err != nil {
+			return err
+		}
 	}
 
-	client, err := ssm.NewClient(cfg.Region)
+	client, err := newClient(cfg.Region)
 	if err != nil {
 		return fmt.Errorf("failed to create SSM client: %w", err)
 	}
 
-	secret, err := client.ReadSecret(path)
+	// A glob reads every match; a single match in text output is shown as usual
+	if glob {
+		names, err := globSecrets(client, path)
+		if err != nil {
+			return err
+		}
+		if len(names) != 1 || readTable || cfg.Output != "text" {
+			if !readTable && cfg.Output == "text" {
+				if len(names) == 0 {
+					fmt.Fprintln(statusOut, ui.Warningf("No secrets match %s", path))
+					return nil
+				}
+				return fmt.Errorf("%s matches %d secrets; use --output json or --table to read them all", path, len(names))
+			}
+			return printGlobRead(client, path, names)
+		}
+		path = names[0]
+	}
+
+	usedDefault := false
+	var secret *ssm.Secret
+	if readMinVersion > 0 {
+		secret, err = readMinimumVersion(client, path, readMinVersion, readRetryTimeout)
+	} else {
+		secret, err = client.ReadSecret(path)
+	}
 	if err != nil {
-		fmt.Println(ui.Error("Failed to read secret"))
-		return fmt.Errorf("failed to read secret: %w", err)
+		// Only a genuine not-found falls back to --default; access denied
+		// and other errors still fail
+		if !cmd.Flags().Changed("default") || !ssm.IsNotFound(err) {
+			fmt.Fprintln(statusOut, ui.Error("Failed to read secret"))
+			return fmt.Errorf("failed to read secret: %w", err)
+		}
+		secret = &ssm.Secret{Name: path, Value: readDefault}
+		usedDefault = true
+	}
+	secret.Tags = redactTags(secret.Tags)
+
+	if readCopy {
+		return copySecret(secret)
+	}
+
+	if readFingerprint {
+		return printFingerprint(secret)
+	}
+
+	if readCertInfo {
+		return printCertInfo(secret)
+	}
+
+	if readJSONPath != "" {
+		return printJSONPath(secret, readJSONPath)
+	}
+
+	secret.Value = shownValue(secret.Value)
+
+	// The raw bytes as base64 on one line, whatever they contain
+	if cfg.Output == "raw-base64" {
+		fmt.Fprintln(out, base64.StdEncoding.EncodeToString([]byte(secret.Value)))
+		return nil
 	}
 
 	// Quiet mode - just output the value
 	if readQuiet {
-		fmt.Print(secret.Value)
+		fmt.Fprint(out, secret.Value)
 		return nil
 	}
 
 	switch cfg.Output {
-	case "json":
+	case "json", "yaml":
 		output := map[string]interface{}{
 			"name":    secret.Name,
 			"value":   secret.Value,
@@ -85,44 +375,419 @@ func runRead(cmd *cobra.Command, args []string) error {
 		if len(secret.Tags) > 0 {
 			output["tags"] = secret.Tags
 		}
-		data, err := json.MarshalIndent(output, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
+		if usedDefault {
+			output["default"] = true
+		}
+		if fields != nil {
+			if output, err = selectFields(output, fields); err != nil {
+				return err
+			}
+		}
+		if err := printStructured(output); err != nil {
+			return err
 		}
-		fmt.Println(string(data))
 	default:
-		fmt.Println()
-		fmt.Println(ui.SectionHeader("Secret"))
-		fmt.Println()
+		if usedDefault {
+			fmt.Fprintln(out)
+			fmt.Fprintln(statusOut, ui.Warningf("%s not found, showing --default value", path))
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.Highlight(secret.Value))
+			fmt.Fprintln(out)
+			return nil
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader("Secret"))
+		fmt.Fprintln(out)
 
 		rows := [][]string{
 			{"Name", secret.Name},
-			{"Value", ui.Highlight(secret.Value)},
+			{"Value", ui.Highlight(fitValue(secret.Value, valueColumnWidth()))},
 			{"Type", secret.Type},
 			{"Version", fmt.Sprintf("%d", secret.Version)},
 		}
-		fmt.Println(ui.Table([]string{"Property", "Value"}, rows))
+		fmt.Fprintln(out, ui.Table([]string{"Property", "Value"}, rows))
 
 		if len(secret.Tags) > 0 {
-			fmt.Println()
-			fmt.Println(ui.SectionHeader("Tags"))
-			fmt.Println()
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.SectionHeader("Tags"))
+			fmt.Fprintln(out)
 
 			tagRows := make([][]string, 0, len(secret.Tags))
 			for k, v := range secret.Tags {
 				tagRows = append(tagRows, []string{k, v})
 			}
-			fmt.Println(ui.Table([]string{"Key", "Value"}, tagRows))
+			fmt.Fprintln(out, ui.Table([]string{"Key", "Value"}, tagRows))
 		}
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
 
 	return nil
 }
 
+// valueColumnWidth returns how wide the read table's value column can be
+// without wrapping, or 0 if output isn't a terminal (nothing is cut)
+func valueColumnWidth() int {
+	if out != os.Stdout || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return 0
+	}
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= readTableOverhead {
+		return 0
+	}
+	return width - readTableOverhead
+}
+
+// fitValue fits each line of value into width columns: cut with an ellipsis
+// and a count of the hidden characters, or with --full broken into chunks
+// of width so the table wraps cleanly. A width of 0 leaves value unchanged.
+func fitValue(value string, width int) string {
+	if width <= 0 {
+		return value
+	}
+
+	lines := strings.Split(value, "\n")
+	var fitted []string
+	for _, line := range lines {
+		runes := []rune(line)
+		if len(runes) <= width {
+			fitted = append(fitted, line)
+			continue
+		}
+		if readFull {
+			for len(runes) > width {
+				fitted = append(fitted, string(runes[:width]))
+				runes = runes[width:]
+			}
+			fitted = append(fitted, string(runes))
+			continue
+		}
+
+		more := fmt.Sprintf("… (+%d, --full)", len(runes))
+		keep := width - len([]rune(more))
+		if keep < 1 {
+			keep = 1
+		}
+		more = fmt.Sprintf("… (+%d, --full)", len(runes)-keep)
+		fitted = append(fitted, string(runes[:keep])+more)
+	}
+	return strings.Join(fitted, "\n")
+}
+
+// copySecret puts the secret's value on the clipboard, printing only a
+// confirmation, and with --clear-after waits to clear it again. An interrupt
+// clears it straight away.
+func copySecret(secret *ssm.Secret) error {
+	if err := copyToClipboard(secret.Value); err != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to copy to clipboard"))
+		return fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	fmt.Fprintln(statusOut, ui.Successf("Copied %s to the clipboard", secret.Name))
+	if readClearAfter == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(statusOut, ui.Infof("Clearing the clipboard in %s (Ctrl-C clears it now)", readClearAfter))
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt)
+	defer signal.Stop(interrupted)
+
+	cleared := make(chan error, 1)
+	go func() {
+		select {
+		case <-time.After(readClearAfter):
+		case <-interrupted:
+		}
+		cleared <- clearClipboard(secret.Value)
+	}()
+
+	if err := <-cleared; err != nil {
+		return fmt.Errorf("failed to clear clipboard: %w", err)
+	}
+	fmt.Fprintln(statusOut, ui.Success("Clipboard cleared"))
+	return nil
+}
+
+// printJSONPath evaluates expr against a JSON-valued secret and prints the
+// result: strings as-is, anything else as JSON
+func printJSONPath(secret *ssm.Secret, expr string) error {
+	doc, err := decodeJSONValue(secret.Value)
+	if err != nil {
+		return fmt.Errorf("can't apply --jsonpath to %s: %w", secret.Name, err)
+	}
+
+	result, err := jsonpath.Get(expr, doc)
+	if err != nil {
+		return fmt.Errorf("jsonpath %s: %w", expr, err)
+	}
+
+	if str, ok := result.(string); ok && cfg.Output == "text" {
+		if readQuiet {
+			fmt.Fprint(out, str)
+		} else {
+			fmt.Fprintln(out, str)
+		}
+		return nil
+	}
+
+	return printStructured(result)
+}
+
+// decodeJSONValue parses a secret value for the flags that need JSON
+// (--jsonpath). Values are otherwise opaque strings, so this is the only
+// place a value that merely looks like JSON is rejected, and the error says
+// exactly why: a byte order mark, a syntax error and where it is, or extra
+// data after the first JSON value. Surrounding whitespace is allowed.
+func decodeJSONValue(value string) (interface{}, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("value is not valid JSON: value is empty")
+	}
+	if strings.HasPrefix(value, "\uFEFF") {
+		return nil, fmt.Errorf("value is not valid JSON: it starts with a byte order mark")
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(strings.NewReader(value))
+	if err := dec.Decode(&doc); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("value is not valid JSON: %v (at byte %d)", syntaxErr, syntaxErr.Offset)
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("value is not valid JSON: it ends before the JSON value is complete")
+		}
+		return nil, fmt.Errorf("value is not valid JSON: %w", err)
+	}
+
+	// Anything but whitespace after the first value (e.g. two objects, or a
+	// JSON prefix followed by text) is rejected rather than ignored
+	offset := dec.InputOffset()
+	if rest := strings.TrimSpace(value[offset:]); rest != "" {
+		return nil, fmt.Errorf("value is not valid JSON: unexpected data after the JSON value (at byte %d)", offset)
+	}
+	return doc, nil
+}
+
+// printFingerprint shows a secret's length and SHA-256 fingerprint so two
+// people can compare values without exposing them
+func printFingerprint(secret *ssm.Secret) error {
+	sum := fingerprint(secret.Value)
+
+	if readQuiet {
+		fmt.Fprintln(out, sum)
+		return nil
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		output := map[string]interface{}{
+			"name":    secret.Name,
+			"version": secret.Version,
+			"length":  len(secret.Value),
+			"sha256":  sum,
+		}
+		if readReveal {
+			output["value"] = secret.Value
+		}
+		if err := printStructured(output); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader("Fingerprint"))
+		fmt.Fprintln(out)
+
+		rows := [][]string{
+			{"Name", secret.Name},
+			{"Version", fmt.Sprintf("%d", secret.Version)},
+			{"Length", fmt.Sprintf("%d bytes", len(secret.Value))},
+			{"SHA-256", ui.Highlight(sum)},
+		}
+		if readReveal {
+			rows = append(rows, []string{"Value", ui.Highlight(secret.Value)})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Property", "Value"}, rows))
+		fmt.Fprintln(out)
+	}
+
+	return nil
+}
+
+// runReadEquals checks path against --equals without printing the value.
+// A mismatch or missing secret exits 1; with --quiet nothing is printed at all.
+func runReadEquals(cmd *cobra.Command, path string) error {
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		if readQuiet {
+			return silentExit(cmd, 1)
+		}
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var secret *ssm.Secret
+	if readMinVersion > 0 {
+		secret, err = readMinimumVersion(client, path, readMinVersion, readRetryTimeout)
+	} else {
+		secret, err = client.ReadSecret(path)
+	}
+	if err != nil {
+		if readQuiet {
+			return silentExit(cmd, 1)
+		}
+		if ssm.IsNotFound(err) {
+			fmt.Fprintln(statusOut, ui.Errorf("%s not found", path))
+			return silentExit(cmd, 1)
+		}
+		return fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	match := subtle.ConstantTimeCompare([]byte(secret.Value), []byte(readEquals)) == 1
+	if !readQuiet {
+		if match {
+			fmt.Fprintln(statusOut, ui.Successf("%s matches", path))
+		} else {
+			fmt.Fprintln(statusOut, ui.Errorf("%s does not match", path))
+		}
+	}
+	if !match {
+		return silentExit(cmd, 1)
+	}
+	return nil
+}
+
+// readMinimumVersion re-reads path until its version is at least minVersion
+// or timeout elapses. Not-found is treated as stale, since a new secret may
+// not have propagated yet.
+func readMinimumVersion(client *ssm.Client, path string, minVersion int64, timeout time.Duration) (*ssm.Secret, error) {
+	deadline := time.Now().Add(timeout)
+	delay := 500 * time.Millisecond
+
+	var secret *ssm.Secret
+	var readErr error
+	_ = spinner.New().
+		Title(fmt.Sprintf("Waiting for version %d...", minVersion)).
+		Action(func() {
+			for {
+				s, err := client.ReadSecret(path)
+				switch {
+				case err == nil && s.Version >= minVersion:
+					secret = s
+					return
+				case err != nil && !ssm.IsNotFound(err):
+					readErr = err
+					return
+				}
+
+				if time.Now().Add(delay).After(deadline) {
+					if err != nil {
+						readErr = fmt.Errorf("timed out after %s waiting for version %d (secret not found)", timeout, minVersion)
+					} else {
+						readErr = fmt.Errorf("timed out after %s waiting for version %d (latest seen: %d)", timeout, minVersion, s.Version)
+					}
+					return
+				}
+
+				time.Sleep(delay)
+				if delay < 5*time.Second {
+					delay *= 2
+				}
+			}
+		}).
+		Run()
+
+	return secret, readErr
+}
+
+// runReadAll reads every secret under path and outputs them keyed by
+// relative path
+func runReadAll(path string) error {
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var secrets []ssm.Secret
+	var readErr error
+	_ = spinner.New().
+		Title("Reading secrets...").
+		Action(func() {
+			secrets, readErr = client.ReadSecrets(path, true)
+		}).
+		Run()
+
+	if readErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to read secrets"))
+		return fmt.Errorf("failed to read secrets: %w", readErr)
+	}
+
+	values := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		if secureOnly && s.Type != ssm.SecureStringType {
+			continue
+		}
+		values[relativeName(s.Name, path)] = shownValue(s.Value)
+	}
+
+	// Quiet mode and JSON/YAML output emit the map for scripts
+	if readQuiet || cfg.Output != "text" {
+		if readNested {
+			flat := make(map[string]interface{}, len(values))
+			for k, v := range values {
+				flat[k] = v
+			}
+			nested, err := nestPaths(flat)
+			if err != nil {
+				return err
+			}
+			return printStructured(nested)
+		}
+		return printStructured(values)
+	}
+
+	if len(values) == 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("No secrets found at %s", path))
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.SectionHeader(path))
+	fmt.Fprintln(out)
+
+	rows := sortedKeyValueRows(values)
+	for _, row := range rows {
+		row[1] = ui.Highlight(row[1])
+	}
+	fmt.Fprintln(out, ui.Table([]string{"Key", "Value"}, rows))
+	fmt.Fprintln(out)
+
+	return nil
+}
+
+// confirmReveal asks before a value picked interactively is shown, so it
+// isn't flashed on a shared screen by accident (confirm_reveal)
+func confirmReveal(path string) (bool, error) {
+	var reveal bool
+	confirm := huh.NewConfirm().
+		Title(fmt.Sprintf("Reveal value for %s?", path)).
+		Value(&reveal)
+	confirm.WithTheme(ui.Theme())
+	if err := confirm.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return false, nil
+		}
+		return false, err
+	}
+	return reveal, nil
+}
+
 // interactiveSecretSearch fetches all secrets and lets user fuzzy-search/select
 func interactiveSecretSearch() (string, error) {
-	client, err := ssm.NewClient(cfg.Region)
+	root, err := defaultPath(nil)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := newClient(cfg.Region)
 	if err != nil {
 		return "", fmt.Errorf("failed to create SSM client: %w", err)
 	}
@@ -132,24 +797,25 @@ func interactiveSecretSearch() (string, error) {
 	_ = spinner.New().
 		Title("Fetching secrets...").
 		Action(func() {
-			secrets, listErr = client.ListSecrets("/", true)
+			secrets, listErr = client.ListSecrets(root, true)
 		}).
 		Run()
 
 	if listErr != nil {
-		fmt.Println(ui.Error("Failed to list secrets"))
+		fmt.Fprintln(statusOut, ui.Error("Failed to list secrets"))
 		return "", fmt.Errorf("failed to list secrets: %w", listErr)
 	}
 
 	if len(secrets) == 0 {
-		fmt.Println(ui.Warning("No secrets found"))
+		fmt.Fprintln(statusOut, ui.Warning("No secrets found"))
 		return "", nil
 	}
 
-	items := make([]pickItem, len(secrets))
+	names := make([]string, len(secrets))
 	for i, s := range secrets {
-		items[i] = pickItem{display: s.Name, search: s.Name, value: s.Name}
+		names[i] = s.Name
 	}
+	items := secretPickItems(names)
 
 	fmt.Println()
 	fmt.Println(ui.Infof("Found %d secrets", len(secrets)))
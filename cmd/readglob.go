@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	pathpkg "path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+)
+
+// globChars are the path.Match metacharacters that make a read path a glob
+const globChars = "*?[\\"
+
+// isGlob reports whether a read path is a glob pattern
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, globChars)
+}
+
+// globBase returns the part of pattern before the first segment with a glob
+// metacharacter: the path to list recursively to find the matches
+func globBase(pattern string) string {
+	i := strings.IndexAny(pattern, globChars)
+	if i < 0 {
+		return pattern
+	}
+	return apiPath(pattern[:strings.LastIndex(pattern[:i], "/")+1])
+}
+
+// globSecrets returns the names of the secrets matching pattern (path.Match
+// syntax, so * doesn't cross a /), sorted
+func globSecrets(client *ssm.Client, pattern string) ([]string, error) {
+	if _, err := pathpkg.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+
+	var secrets []ssm.SecretMetadata
+	var listErr error
+	_ = spinner.New().
+		Title("Finding secrets...").
+		Action(func() {
+			secrets, listErr = client.ListSecrets(globBase(pattern), true)
+		}).
+		Run()
+	if listErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to list secrets"))
+		return nil, fmt.Errorf("failed to list secrets: %w", listErr)
+	}
+
+	var names []string
+	for _, s := range secrets {
+		if ok, _ := pathpkg.Match(pattern, s.Name); ok {
+			names = append(names, s.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// printGlobRead batch-reads names and prints them as a map of name to value
+// (JSON/YAML, or --quiet) or, with --table, as a table
+func printGlobRead(client *ssm.Client, pattern string, names []string) error {
+	var secrets []ssm.Secret
+	var readErr error
+	_ = spinner.New().
+		Title("Reading secrets...").
+		Action(func() {
+			secrets, readErr = client.ReadSecretsByName(names)
+		}).
+		Run()
+	if readErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to read secrets"))
+		return fmt.Errorf("failed to read secrets: %w", readErr)
+	}
+
+	values := make(map[string]string, len(secrets))
+	for _, s := range secrets {
+		values[s.Name] = shownValue(s.Value)
+	}
+
+	if !readTable {
+		return printStructured(values)
+	}
+
+	if len(values) == 0 {
+		fmt.Fprintln(statusOut, ui.Warningf("No secrets match %s", pattern))
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, ui.SectionHeader(pattern))
+	fmt.Fprintln(out)
+
+	rows := sortedKeyValueRows(values)
+	for _, row := range rows {
+		row[1] = ui.Highlight(row[1])
+	}
+	fmt.Fprintln(out, ui.Table([]string{"Name", "Value"}, rows))
+	fmt.Fprintln(out)
+	return nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	rekeyKMSKey    string
+	rekeyRecursive bool
+	rekeyForce     bool
+)
+
+var rekeyCmd = &cobra.Command{
+	Use:   "rekey <path>",
+	Short: "Re-encrypt secrets under a different KMS key",
+	Long: `Re-encrypt a SecureString under a different KMS key, e.g. to move from
+the AWS managed key (alias/aws/ssm) to a customer managed key.
+
+SSM has no way to change the key in place, so each value is read and written
+back with the new key. This creates a new version; the type, tier and
+description are kept. With --recursive, every SecureString under the path is
+rekeyed. Secrets already using the key (as reported by SSM) and plain
+String/StringList parameters are skipped.
+
+The secrets to rekey are listed and you're asked to confirm (--force skips
+this). A failure doesn't stop the remaining secrets.
+
+Examples:
+  lockr rekey /myapp/prod/api-key --kms-key alias/myapp
+
+  # Migrate a whole tree
+  lockr rekey /myapp --recursive --kms-key alias/myapp
+
+  # Find secrets still on the AWS managed key first
+  lockr list / --recursive --group-by-key`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRekey,
+}
+
+func init() {
+	rootCmd.AddCommand(rekeyCmd)
+
+	rekeyCmd.Flags().StringVar(&rekeyKMSKey, "kms-key", "", "KMS key to re-encrypt with (alias, ID or ARN; required)")
+	rekeyCmd.Flags().BoolVarP(&rekeyRecursive, "recursive", "r", false, "rekey every SecureString under the path")
+	rekeyCmd.Flags().BoolVar(&rekeyForce, "force", false, "skip the confirmation")
+	_ = rekeyCmd.MarkFlagRequired("kms-key")
+}
+
+func runRekey(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var secrets []ssm.SecretMetadata
+	var descErr error
+	_ = spinner.New().
+		Title("Finding secrets...").
+		Action(func() {
+			if rekeyRecursive {
+				secrets, descErr = client.DescribeSecrets(path, true)
+				return
+			}
+			var meta *ssm.SecretMetadata
+			meta, descErr = client.DescribeSecret(path)
+			if descErr == nil {
+				secrets = []ssm.SecretMetadata{*meta}
+			}
+		}).
+		Run()
+	if descErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to find secrets"))
+		return fmt.Errorf("failed to find secrets: %w", descErr)
+	}
+
+	var paths []string
+	for _, s := range secrets {
+		if s.Type == ssm.SecureStringType && s.KeyID != rekeyKMSKey {
+			paths = append(paths, s.Name)
+		}
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(statusOut, ui.Infof("Nothing to rekey: no SecureStrings at %s use a key other than %s", path, rekeyKMSKey))
+		return nil
+	}
+
+	if !skipConfirm(rekeyForce) {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("rekeying %d secret(s) needs confirmation; pass --force when not running in a terminal", len(paths))
+		}
+
+		fmt.Println()
+		for _, p := range paths {
+			fmt.Println("  " + ui.Highlight(p))
+		}
+		fmt.Println()
+
+		var confirmed bool
+		confirm := huh.NewConfirm().
+			Title(fmt.Sprintf("Re-encrypt these %d secret(s) with %s?", len(paths), rekeyKMSKey)).
+			Value(&confirmed)
+		confirm.WithTheme(ui.Theme())
+		if err := confirm.Run(); err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println(ui.Info("Cancelled"))
+			return nil
+		}
+	}
+
+	results := make([]writeResult, len(paths))
+	for i, p := range paths {
+		results[i] = writeResult{Path: p, Status: "rekeyed"}
+		if err := client.Rekey(p, rekeyKMSKey); err != nil {
+			results[i] = writeResult{Path: p, Status: "failed", Error: err.Error()}
+		}
+		if cfg.Output == "text" {
+			if results[i].Status == "failed" {
+				fmt.Println(ui.Errorf("[%d/%d] Failed to rekey %s: %s", i+1, len(paths), p, results[i].Error))
+			} else {
+				fmt.Println(ui.Successf("[%d/%d] Rekeyed %s", i+1, len(paths), p))
+			}
+		}
+	}
+
+	rekeyed, failed := resultCounts(results)
+	emitMetrics(client, "rekey", rekeyed, failed)
+
+	if cfg.Output != "text" {
+		if err := printStructured(results); err != nil {
+			return err
+		}
+	} else {
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to rekey %d of %d secrets", failed, len(paths))
+	}
+	return nil
+}
@@ -1,21 +1,53 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/charmbracelet/huh"
+	"github.com/devops-chris/clihq/ui"
 	"github.com/devops-chris/lockr/internal/config"
+	"github.com/devops-chris/lockr/internal/ssm"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
-	cfg       *config.Config
-	cfgFile   string
-	version   = "dev"
-	commit    = "none"
-	buildDate = "unknown"
+	cfg        *config.Config
+	cfgFile    string
+	noCfgFile  bool
+	cfgErr     error
+	checkCreds bool
+	version    = "dev"
+	commit     = "none"
+	buildDate  = "unknown"
 )
 
+// outputFormats are the accepted values for --output
+var outputFormats = []string{"text", "json", "yaml", "tsv", "csv", "tfvars", "raw-base64"}
+
+// tsvAnnotation marks the commands that support --output tsv
+const tsvAnnotation = "lockr.output.tsv"
+
+// csvAnnotation marks the commands that support --output csv
+const csvAnnotation = "lockr.output.csv"
+
+// tfvarsAnnotation marks the commands that support --output tfvars
+const tfvarsAnnotation = "lockr.output.tfvars"
+
+// rawBase64Annotation marks the commands that support --output raw-base64
+const rawBase64Annotation = "lockr.output.raw-base64"
+
+// outFileAnnotation marks the commands that support --out-file
+const outFileAnnotation = "lockr.output.out-file"
+
 // SetVersion sets the version info from build flags
 func SetVersion(v, c, d string) {
 	version = v
@@ -29,14 +61,31 @@ var rootCmd = &cobra.Command{
 	Long: `lockr - A simple CLI for managing secrets in AWS SSM Parameter Store.
 
 Works with zero configuration using sane defaults.
-Configuration precedence: CLI flags > ENV vars > Config file > Defaults
+Configuration precedence: CLI flags > ENV vars > .lockr.yaml (project) >
+~/.config/lockr/config.yaml (user) > Defaults
 
 Environment variables:
   LOCKR_PREFIX   Path prefix for relative paths (e.g., /infra/saas)
+  LOCKR_REQUIRE_PREFIX     Reject paths outside the prefix (unless --allow-absolute)
   LOCKR_ENV      Environment to include in path (e.g., prod, staging)
-  LOCKR_OUTPUT   Output format: text, json (default: text)
+  LOCKR_OUTPUT   Output format: text, json, yaml, tsv, csv, tfvars, raw-base64 (default: text)
   LOCKR_KMS_KEY  KMS key alias (default: alias/aws/ssm)
   LOCKR_REGION   AWS region (default: from AWS config)
+  LOCKR_EXPECTED_REGION    Confirm before changing secrets in any other region
+  LOCKR_CONFIRM_WORD       Word to type to confirm delete/move (default: the path)
+  LOCKR_ASSUME_YES         Skip ALL confirmation prompts (dangerous; automation only)
+  LOCKR_RATE_LIMIT         Max SSM API requests per second (default: unlimited)
+  LOCKR_EMIT_METRICS       Publish CloudWatch metrics for writes/deletes
+  LOCKR_METRICS_NAMESPACE  CloudWatch namespace for metrics (default: lockr)
+  LOCKR_CONFIRM_REVEAL     Ask before showing a value picked interactively
+  LOCKR_REDACT             Mask every secret value as *** (same as --redact)
+  LOCKR_REDACT_TAGS        Tag keys whose values are shown as *** (comma-separated)
+  LOCKR_AUTO_TAGS          Tag writes with lockr:last-writer and lockr:written-at
+  LOCKR_AWS_CONFIG_FILE       AWS shared config file (default: ~/.aws/config)
+  LOCKR_AWS_CREDENTIALS_FILE  AWS shared credentials file (default: ~/.aws/credentials)
+  LOCKR_CACHE_CREDENTIALS     Cache assumed-role credentials on disk until they expire
+  LOCKR_ROLE_ARN              Roles to assume in order (comma-separated role chain)
+  LOCKR_EXTERNAL_ID           External IDs for LOCKR_ROLE_ARN, by position (comma-separated)
 
 Examples:
   # Write a secret (prompts for value)
@@ -59,27 +108,95 @@ Examples:
 
   # Delete a secret
   lockr delete /myapp/prod/old-key`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateConfig(); err != nil {
+			return err
+		}
+		if cfg.Output == "tsv" && cmd.Annotations[tsvAnnotation] == "" {
+			return fmt.Errorf("--output tsv is only supported by list and describe")
+		}
+		if cfg.Output == "csv" && cmd.Annotations[csvAnnotation] == "" {
+			return fmt.Errorf("--output csv is only supported by report")
+		}
+		if cfg.Output == "tfvars" && cmd.Annotations[tfvarsAnnotation] == "" {
+			return fmt.Errorf("--output tfvars is only supported by export")
+		}
+		if cfg.Output == "raw-base64" && cmd.Annotations[rawBase64Annotation] == "" {
+			return fmt.Errorf("--output raw-base64 is only supported by read")
+		}
+		if f := cmd.Flags().Lookup("reveal"); cfg.Redact && f != nil && f.Changed {
+			return fmt.Errorf("--reveal cannot be used with --redact")
+		}
+		if outFile != "" && cmd.Annotations[outFileAnnotation] == "" {
+			return fmt.Errorf("--out-file is only supported by read, list and export")
+		}
+		return openOutput()
+	},
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if closeErr := closeOutput(); err == nil {
+		err = closeErr
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
+// exitError ends the process with a specific exit code without printing an
+// error message. Create it with silentExit so cobra doesn't print it either.
+type exitError struct {
+	code int
+}
+
+func (e *exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// silentExit returns an error that makes lockr exit with code and no output
+func silentExit(cmd *cobra.Command, code int) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return &exitError{code: code}
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default: ~/.config/lockr/config.yaml)")
+	rootCmd.PersistentFlags().BoolVar(&noCfgFile, "no-config-file", false, "ignore all config files; use only flags and env vars")
 	rootCmd.PersistentFlags().String("prefix", "", "path prefix for secrets")
 	rootCmd.PersistentFlags().String("env", "", "environment (e.g., prod, staging)")
-	rootCmd.PersistentFlags().String("output", "text", "output format (text, json)")
+	rootCmd.PersistentFlags().String("output", "text", "output format (text, json, yaml, tsv for list/describe, csv for report, tfvars for export, raw-base64 for read)")
 	rootCmd.PersistentFlags().String("region", "", "AWS region (default: from AWS config)")
+	rootCmd.PersistentFlags().StringVar(&confirmedRegion, "confirm-region", "", "allow changes in this region even though it isn't expected_region")
+	rootCmd.PersistentFlags().BoolVar(&allowAbsolute, "allow-absolute", false, "allow paths outside the prefix even though require_prefix is set")
+	rootCmd.PersistentFlags().String("aws-config-file", "", "AWS shared config file (default: ~/.aws/config)")
+	rootCmd.PersistentFlags().String("aws-credentials-file", "", "AWS shared credentials file (default: ~/.aws/credentials)")
+	rootCmd.PersistentFlags().StringSlice("role-arn", nil, "assume these roles in order (comma-separated or repeated) for a role chain")
+	rootCmd.PersistentFlags().StringSlice("external-id", nil, "external ID for each --role-arn, by position (leave empty for none)")
+	rootCmd.PersistentFlags().Bool("cache-credentials", false, "cache assumed-role credentials on disk until they expire (fewer MFA prompts)")
+	rootCmd.PersistentFlags().Float64("rate-limit", 0, "max SSM API requests per second (0 = unlimited)")
+	rootCmd.PersistentFlags().StringVar(&outFile, "out-file", "", "write primary output (read, list, export) to a file with 0600 permissions")
+	rootCmd.PersistentFlags().BoolVar(&checkCreds, "check-creds", false, "verify AWS credentials before running the command")
+	rootCmd.PersistentFlags().Bool("emit-metrics", false, "publish a CloudWatch metric for writes/deletes (best-effort)")
+	rootCmd.PersistentFlags().Bool("redact", false, "mask every secret value in output as *** (for demos and screen recordings)")
 }
 
 func initConfig() {
-	cfg = config.Load(cfgFile)
+	cfg, cfgErr = config.Load(cfgFile, noCfgFile)
+	// A broken config file given with --config fails the command (see
+	// validateConfig); a discovered one only warns
+	if cfgErr != nil && cfgFile == "" {
+		fmt.Fprintln(os.Stderr, ui.Warningf("Problem loading config: %v", cfgErr))
+	}
 
 	// Override with CLI flags if provided
 	if prefix, _ := rootCmd.PersistentFlags().GetString("prefix"); prefix != "" {
@@ -94,4 +211,157 @@ func initConfig() {
 	if region, _ := rootCmd.PersistentFlags().GetString("region"); region != "" {
 		cfg.Region = region
 	}
+	if file, _ := rootCmd.PersistentFlags().GetString("aws-config-file"); file != "" {
+		cfg.AWSConfigFile = file
+	}
+	if file, _ := rootCmd.PersistentFlags().GetString("aws-credentials-file"); file != "" {
+		cfg.AWSCredentialsFile = file
+	}
+	if roles, _ := rootCmd.PersistentFlags().GetStringSlice("role-arn"); len(roles) > 0 {
+		cfg.RoleARNs = roles
+	}
+	if ids, _ := rootCmd.PersistentFlags().GetStringSlice("external-id"); len(ids) > 0 {
+		cfg.ExternalIDs = ids
+	}
+	if cache, _ := rootCmd.PersistentFlags().GetBool("cache-credentials"); cache {
+		cfg.CacheCredentials = true
+	}
+	if rateLimit, _ := rootCmd.PersistentFlags().GetFloat64("rate-limit"); rateLimit > 0 {
+		cfg.RateLimit = rateLimit
+	}
+	if emit, _ := rootCmd.PersistentFlags().GetBool("emit-metrics"); emit {
+		cfg.EmitMetrics = true
+	}
+	if redact, _ := rootCmd.PersistentFlags().GetBool("redact"); redact {
+		cfg.Redact = true
+	}
+}
+
+// validateConfig checks the resolved configuration before any command runs
+func validateConfig() error {
+	if noCfgFile && cfgFile != "" {
+		return fmt.Errorf("--config and --no-config-file are mutually exclusive")
+	}
+	if cfgErr != nil && cfgFile != "" {
+		return cfgErr
+	}
+	if !slices.Contains(outputFormats, cfg.Output) {
+		return fmt.Errorf("invalid output format %q (use %s)", cfg.Output, strings.Join(outputFormats, ", "))
+	}
+	if len(cfg.ExternalIDs) > len(cfg.RoleARNs) {
+		return fmt.Errorf("more --external-id values (%d) than --role-arn roles (%d)", len(cfg.ExternalIDs), len(cfg.RoleARNs))
+	}
+	if cfg.RateLimit < 0 {
+		return fmt.Errorf("rate limit must not be negative")
+	}
+	if cfg.RequirePrefix && strings.Trim(cfg.Prefix, "/") == "" {
+		return fmt.Errorf("require_prefix is set but no prefix is configured")
+	}
+	return nil
+}
+
+// checkCredentials fails fast if AWS credentials are missing or expired. It
+// runs from newClient, once the command's paths (and so its profile) are
+// known, and before the client is first used.
+func checkCredentials(client *ssm.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.CheckCredentials(ctx); err != nil {
+		return fmt.Errorf("AWS credentials check failed: %w", err)
+	}
+	return nil
+}
+
+// clients caches one SSM client per region and profile so credentials (and
+// any MFA prompt) are resolved once per run
+var (
+	clients   = map[string]*ssm.Client{}
+	clientsMu sync.Mutex
+)
+
+// newClient returns the SSM client for region with the configured client
+// options, creating it on first use. Its profile comes from the paths
+// resolved so far, so commands call buildPath for all of theirs first.
+func newClient(region string) (*ssm.Client, error) {
+	profile, err := pathProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	key := region + "\x00" + profile
+	if client, ok := clients[key]; ok {
+		return client, nil
+	}
+	client, err := ssm.NewClient(region,
+		ssm.WithProfile(profile),
+		ssm.WithRateLimit(cfg.RateLimit),
+		ssm.WithSharedConfigFile(cfg.AWSConfigFile),
+		ssm.WithSharedCredentialsFile(cfg.AWSCredentialsFile),
+		ssm.WithMFATokenProvider(promptMFAToken),
+		ssm.WithCredentialCache(credentialCacheDir()),
+		ssm.WithRoleChain(roleChain()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if checkCreds {
+		if err := checkCredentials(client); err != nil {
+			return nil, err
+		}
+	}
+	clients[key] = client
+	useProfile(profile)
+	return client, nil
+}
+
+// roleChain pairs --role-arn with --external-id by position
+func roleChain() []ssm.AssumeRole {
+	roles := make([]ssm.AssumeRole, len(cfg.RoleARNs))
+	for i, arn := range cfg.RoleARNs {
+		roles[i].RoleARN = arn
+		if i < len(cfg.ExternalIDs) {
+			roles[i].ExternalID = cfg.ExternalIDs[i]
+		}
+	}
+	return roles
+}
+
+// credentialCacheDir returns where assumed-role sessions are cached, or "" if
+// caching is off
+func credentialCacheDir() string {
+	if !cfg.CacheCredentials {
+		return ""
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lockr", "credentials")
+}
+
+// promptMFAToken asks for the MFA code of a role that requires one
+func promptMFAToken() (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("the AWS profile requires an MFA code but lockr is not running in a terminal")
+	}
+
+	var code string
+	input := huh.NewInput().
+		Title("MFA code").
+		Validate(func(s string) error {
+			if len(s) != 6 || strings.Trim(s, "0123456789") != "" {
+				return fmt.Errorf("enter the 6-digit code")
+			}
+			return nil
+		}).
+		Value(&code)
+	input.WithTheme(ui.Theme())
+
+	if err := input.Run(); err != nil {
+		return "", err
+	}
+	return code, nil
 }
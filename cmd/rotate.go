@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rotateCommand  string
+	rotateValueVia string
+)
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate <path>",
+	Short: "Rotate a secret using a custom script",
+	Long: `Rotate a secret by running a rotation script and storing its output.
+
+The script receives the current value (on stdin by default, or in the
+LOCKR_CURRENT_VALUE environment variable with --value-via env) and the path
+in LOCKR_SECRET_PATH. Whatever it prints to stdout becomes the new value
+(one trailing newline is removed); stderr is passed through. A non-zero exit
+or empty output aborts the rotation without changing anything.
+
+The version that was current before the rotation is labeled 'previous' so it
+can be found for rollback.
+
+Examples:
+  lockr rotate /myapp/prod/db-password --command './rotate-db.sh'
+
+  # Script reads the old value from the environment
+  lockr rotate /myapp/prod/api-key --command './rotate.sh' --value-via env`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRotate,
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+
+	rotateCmd.Flags().StringVar(&rotateCommand, "command", "", "rotation script to run via the shell (required)")
+	rotateCmd.Flags().StringVar(&rotateValueVia, "value-via", "stdin", "how the script receives the current value: stdin or env")
+	_ = rotateCmd.MarkFlagRequired("command")
+}
+
+func runRotate(cmd *cobra.Command, args []string) error {
+	if rotateValueVia != "stdin" && rotateValueVia != "env" {
+		return fmt.Errorf("invalid --value-via %q (use stdin or env)", rotateValueVia)
+	}
+
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	current, err := client.ReadSecret(path)
+	if err != nil {
+		fmt.Println(ui.Error("Failed to read current value"))
+		return fmt.Errorf("failed to read secret: %w", err)
+	}
+
+	// Run outside the spinner so the script's stderr is readable
+	c := shellCommand(rotateCommand)
+	c.Env = append(os.Environ(), "LOCKR_SECRET_PATH="+path)
+	if rotateValueVia == "env" {
+		c.Env = append(c.Env, "LOCKR_CURRENT_VALUE="+current.Value)
+	} else {
+		c.Stdin = strings.NewReader(current.Value)
+	}
+
+	value, err := commandValue(c)
+	if err != nil {
+		fmt.Println(ui.Error("Rotation script failed, secret unchanged"))
+		return fmt.Errorf("rotation script failed: %w", err)
+	}
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "\n"), "\r")
+	if value == "" {
+		fmt.Println(ui.Error("Rotation script printed no value, secret unchanged"))
+		return fmt.Errorf("rotation script produced an empty value")
+	}
+	if value == current.Value {
+		fmt.Println(ui.Error("Rotation script returned the current value, secret unchanged"))
+		return fmt.Errorf("rotation script returned the current value")
+	}
+
+	var writeErr, labelErr error
+	_ = spinner.New().
+		Title("Storing new value...").
+		Action(func() {
+			writeErr = client.WriteSecret(path, value, newAutoTagger(client, false).tags(nil), true, cfg.KMSKey)
+			if writeErr != nil {
+				return
+			}
+			labelErr = client.LabelVersion(path, current.Version, "previous")
+		}).
+		Run()
+	emitOutcome(client, "rotate", writeErr)
+
+	if writeErr != nil {
+		fmt.Println(ui.Error("Failed to store new value"))
+		return fmt.Errorf("failed to write secret: %w", writeErr)
+	}
+
+	fmt.Println(ui.Success("Secret rotated"))
+	fmt.Println()
+	fmt.Println(ui.Subtle("Path: ") + ui.Highlight(path))
+	if labelErr != nil {
+		fmt.Println(ui.Warningf("Could not label version %d as 'previous': %v", current.Version, labelErr))
+	} else {
+		fmt.Println(ui.Subtle("Previous: ") + fmt.Sprintf("version %d (label 'previous')", current.Version))
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/spf13/cobra"
+)
+
+var statsWithSize bool
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [prefix]",
+	Short: "Summarize the parameters under a prefix",
+	Long: `Summarize the parameters under a prefix (recursively): total count and
+breakdown by type and tier. Advanced-tier parameters are billed per parameter
+per month, so they're called out separately.
+
+--with-size also reads (and decrypts) every value to report the approximate
+total value size.
+
+Examples:
+  lockr stats
+  lockr stats /myapp --with-size
+  lockr stats /myapp --output json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().BoolVar(&statsWithSize, "with-size", false, "read values to report total size (requires decrypt access)")
+}
+
+// storeStats is the summary printed by stats
+type storeStats struct {
+	Path       string         `json:"path"`
+	Total      int            `json:"total"`
+	ByType     map[string]int `json:"by_type"`
+	ByTier     map[string]int `json:"by_tier"`
+	Advanced   int            `json:"advanced"`
+	TotalBytes *int           `json:"total_bytes,omitempty"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	path, err := defaultPath(args)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	stats := storeStats{Path: path, ByType: map[string]int{}, ByTier: map[string]int{}}
+	var statsErr error
+	_ = spinner.New().
+		Title("Collecting stats...").
+		Action(func() {
+			metas, err := client.DescribeSecrets(path, true)
+			if err != nil {
+				statsErr = err
+				return
+			}
+			for _, m := range metas {
+				stats.Total++
+				stats.ByType[m.Type]++
+				stats.ByTier[m.Tier]++
+				if m.Tier == "Advanced" {
+					stats.Advanced++
+				}
+			}
+
+			if !statsWithSize {
+				return
+			}
+			secrets, err := client.ReadSecrets(path, true)
+			if err != nil {
+				statsErr = err
+				return
+			}
+			total := 0
+			for _, s := range secrets {
+				total += len(s.Value)
+			}
+			stats.TotalBytes = &total
+		}).
+		Run()
+
+	if statsErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to collect stats"))
+		return fmt.Errorf("failed to collect stats: %w", statsErr)
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if err := printStructured(stats); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, ui.SectionHeader("Stats: "+path))
+		fmt.Fprintln(out)
+
+		rows := [][]string{{"Parameters", fmt.Sprintf("%d", stats.Total)}}
+		for _, row := range sortedCountRows(stats.ByType) {
+			rows = append(rows, []string{"Type: " + row[0], row[1]})
+		}
+		for _, row := range sortedCountRows(stats.ByTier) {
+			rows = append(rows, []string{"Tier: " + row[0], row[1]})
+		}
+		if stats.TotalBytes != nil {
+			rows = append(rows, []string{"Total value size", fmt.Sprintf("%d bytes", *stats.TotalBytes)})
+		}
+		fmt.Fprintln(out, ui.Table([]string{"Metric", "Value"}, rows))
+		fmt.Fprintln(out)
+
+		if stats.Advanced > 0 {
+			fmt.Fprintln(out, ui.Warningf("%d Advanced-tier parameter(s) are billed monthly", stats.Advanced))
+			fmt.Fprintln(out)
+		}
+	}
+
+	return nil
+}
+
+// sortedCountRows returns counts as name/count rows sorted by name
+func sortedCountRows(counts map[string]int) [][]string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name, fmt.Sprintf("%d", counts[name])})
+	}
+	return rows
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devops-chris/clihq/ui"
+)
+
+// batchSummary totals the outcome of an operation on many secrets (apply,
+// import, recursive delete), so CI can gate on one parseable result instead
+// of scraping per-secret lines
+type batchSummary struct {
+	Created   int            `json:"created"`
+	Updated   int            `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Deleted   int            `json:"deleted"`
+	Skipped   int            `json:"skipped"`
+	Failed    int            `json:"failed"`
+	Failures  []batchFailure `json:"failures"`
+}
+
+type batchFailure struct {
+	Path   string `json:"path"`
+	Action string `json:"action"`
+	Error  string `json:"error"`
+}
+
+// succeeded counts a successful (or unchanged) action
+func (s *batchSummary) succeeded(action string) {
+	switch action {
+	case "create":
+		s.Created++
+	case "update":
+		s.Updated++
+	case "delete":
+		s.Deleted++
+	case "unchanged":
+		s.Unchanged++
+	}
+}
+
+// fail records a failed action on path
+func (s *batchSummary) fail(path, action string, err error) {
+	s.Failed++
+	s.Failures = append(s.Failures, batchFailure{Path: path, Action: action, Error: err.Error()})
+}
+
+// printSummaryText prints the non-zero counts on one line, then each failure
+func printSummaryText(s batchSummary) {
+	counts := []struct {
+		n    int
+		what string
+	}{
+		{s.Created, "created"},
+		{s.Updated, "updated"},
+		{s.Unchanged, "unchanged"},
+		{s.Deleted, "deleted"},
+		{s.Skipped, "skipped"},
+		{s.Failed, "failed"},
+	}
+	var parts []string
+	for _, c := range counts {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.what))
+		}
+	}
+	if len(parts) == 0 {
+		parts = []string{"nothing done"}
+	}
+
+	fmt.Fprintln(statusOut, ui.SectionHeader("Summary"))
+	fmt.Fprintln(statusOut, "  "+strings.Join(parts, ", "))
+	if len(s.Failures) > 0 {
+		fmt.Fprintln(statusOut)
+		for _, f := range s.Failures {
+			fmt.Fprintln(statusOut, ui.Errorf("  %s %s: %s", f.Action, f.Path, f.Error))
+		}
+	}
+	fmt.Fprintln(statusOut)
+}
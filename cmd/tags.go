@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/ssm"
+	"github.com/spf13/cobra"
+)
+
+var tagsReplace bool
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Manage tags on a secret",
+	Long: `Manage tags on a secret in AWS SSM Parameter Store.
+
+Examples:
+  # Show tags
+  lockr tags list /myapp/prod/api-key
+
+  # Add or update tags (merges with existing tags)
+  lockr tags add /myapp/prod/api-key owner=platform team=payments
+
+  # Make the tags exactly match (removes any other existing tags)
+  lockr tags add /myapp/prod/api-key owner=platform --replace-tags
+
+  # Remove tags
+  lockr tags remove /myapp/prod/api-key team
+
+  # Add, change and remove several tags interactively
+  lockr tags edit /myapp/prod/api-key`,
+}
+
+var tagsListCmd = &cobra.Command{
+	Use:   "list <path>",
+	Short: "Show tags on a secret",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTagsList,
+}
+
+var tagsAddCmd = &cobra.Command{
+	Use:   "add <path> <key=value>...",
+	Short: "Add or update tags on a secret",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTagsAdd,
+}
+
+var tagsRemoveCmd = &cobra.Command{
+	Use:   "remove <path> <key>...",
+	Short: "Remove tags from a secret",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTagsRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsListCmd, tagsAddCmd, tagsRemoveCmd)
+
+	tagsAddCmd.Flags().BoolVar(&tagsReplace, "replace-tags", false, "replace all existing tags instead of merging")
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	tags, err := client.GetTags(path)
+	if err != nil {
+		fmt.Println(ui.Error("Failed to get tags"))
+		return fmt.Errorf("failed to get tags: %w", err)
+	}
+	tags = redactTags(tags)
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if err := printStructured(tags); err != nil {
+			return err
+		}
+	default:
+		if len(tags) == 0 {
+			fmt.Println(ui.Warningf("No tags on %s", path))
+			return nil
+		}
+
+		fmt.Println()
+		fmt.Println(ui.SectionHeader("Tags"))
+		fmt.Println()
+		fmt.Println(ui.Table([]string{"Key", "Value"}, sortedKeyValueRows(tags)))
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func runTagsAdd(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	tags, err := parseTags(args[1:])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var tagErr error
+	_ = spinner.New().
+		Title("Updating tags...").
+		Action(func() {
+			if tagsReplace {
+				tagErr = client.ReplaceTags(path, tags)
+			} else {
+				tagErr = client.SetTags(path, tags)
+			}
+		}).
+		Run()
+
+	if tagErr != nil {
+		fmt.Println(ui.Error("Failed to update tags"))
+		return fmt.Errorf("failed to update tags: %w", tagErr)
+	}
+
+	fmt.Println(ui.Successf("Tags updated: %s", path))
+	return nil
+}
+
+func runTagsRemove(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var tagErr error
+	_ = spinner.New().
+		Title("Removing tags...").
+		Action(func() {
+			tagErr = client.RemoveTags(path, args[1:])
+		}).
+		Run()
+
+	if tagErr != nil {
+		fmt.Println(ui.Error("Failed to remove tags"))
+		return fmt.Errorf("failed to remove tags: %w", tagErr)
+	}
+
+	fmt.Println(ui.Successf("Tags removed: %s", path))
+	return nil
+}
+
+// sortedKeyValueRows returns a map as key/value table rows sorted by key
+func sortedKeyValueRows(m map[string]string) [][]string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	rows := make([][]string, 0, len(keys))
+	for _, k := range keys {
+		rows = append(rows, []string{k, m[k]})
+	}
+	return rows
+}
+
+// redactTags returns a copy of tags with the values of keys listed in
+// redact_tags replaced by ***. Keys are matched case-insensitively.
+func redactTags(tags map[string]string) map[string]string {
+	if len(cfg.RedactTags) == 0 || len(tags) == 0 {
+		return tags
+	}
+
+	redacted := make(map[string]string, len(tags))
+	for k, v := range tags {
+		redacted[k] = v
+		for _, r := range cfg.RedactTags {
+			if strings.EqualFold(k, r) {
+				redacted[k] = "***"
+				break
+			}
+		}
+	}
+	return redacted
+}
+
+// Tag keys added by auto_tags
+const (
+	lastWriterTag = "lockr:last-writer"
+	writtenAtTag  = "lockr:written-at"
+)
+
+// autoTagger adds the auto_tags provenance tags to the writes of one command.
+// The caller is looked up with STS on the first write that needs it, and only
+// once, so a command that ends up writing nothing (e.g. an unchanged value)
+// makes no STS call. A nil *autoTagger adds nothing.
+type autoTagger struct {
+	client    *ssm.Client
+	writtenAt string
+
+	once   sync.Once
+	caller string
+}
+
+// newAutoTagger returns the autoTagger for client's writes, or nil when
+// auto_tags is off or skip is set (e.g. write --no-auto-tags)
+func newAutoTagger(client *ssm.Client, skip bool) *autoTagger {
+	if !cfg.AutoTags || skip {
+		return nil
+	}
+	return &autoTagger{client: client, writtenAt: time.Now().UTC().Format(time.RFC3339)}
+}
+
+// tags returns tags plus the provenance tags. Tags the user set explicitly
+// win over the automatic ones. If the caller can't be identified,
+// lockr:last-writer is skipped with a warning rather than failing the write.
+func (a *autoTagger) tags(tags map[string]string) map[string]string {
+	if a == nil {
+		return tags
+	}
+
+	a.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		arn, err := a.client.CallerARN(ctx)
+		if err != nil {
+			fmt.Fprintln(statusOut, ui.Warningf("Skipping %s tag: %v", lastWriterTag, err))
+			return
+		}
+		a.caller = arn
+	})
+
+	merged := map[string]string{writtenAtTag: a.writtenAt}
+	if a.caller != "" {
+		merged[lastWriterTag] = a.caller
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	tagEditAdd  = "\x00add"
+	tagEditDone = "\x00done"
+
+	tagEditChange = "change"
+	tagEditRemove = "remove"
+	tagEditBack   = "back"
+)
+
+var tagsEditCmd = &cobra.Command{
+	Use:   "edit <path>",
+	Short: "Edit the tags on a secret interactively",
+	Long: `Edit the tags on a secret interactively.
+
+The current tags are listed; pick one to change its value or remove it, or
+add new ones. When you're done, the changes are shown as a diff and applied
+after you confirm: new and changed tags with one AddTagsToResource call,
+removed ones with one RemoveTagsFromResource call. Esc cancels without
+changing anything.
+
+Values of keys in redact_tags are shown as *** here too; changing one starts
+from an empty value.
+
+Needs a terminal; use tags add and tags remove in scripts.
+
+Examples:
+  lockr tags edit /myapp/prod/api-key`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTagsEdit,
+}
+
+func init() {
+	tagsCmd.AddCommand(tagsEditCmd)
+}
+
+func runTagsEdit(cmd *cobra.Command, args []string) error {
+	path, err := buildPath(args[0])
+	if err != nil {
+		return err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return fmt.Errorf("tags edit needs a terminal; use tags add and tags remove in scripts")
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	var before map[string]string
+	var getErr error
+	_ = spinner.New().
+		Title("Fetching tags...").
+		Action(func() {
+			before, getErr = client.GetTags(path)
+		}).
+		Run()
+	if getErr != nil {
+		fmt.Println(ui.Error("Failed to get tags"))
+		return fmt.Errorf("failed to get tags: %w", getErr)
+	}
+
+	after, err := editTags(path, before)
+	if err != nil {
+		return err
+	}
+	if after == nil {
+		fmt.Println(ui.Info("Cancelled"))
+		return nil
+	}
+
+	set, remove := tagChanges(before, after)
+	if len(set) == 0 && len(remove) == 0 {
+		fmt.Println(ui.Info("No changes"))
+		return nil
+	}
+
+	printTagDiff(before, after)
+
+	if !skipConfirm(false) {
+		var confirmed bool
+		confirm := huh.NewConfirm().
+			Title("Apply these tag changes?").
+			Value(&confirmed)
+		confirm.WithTheme(ui.Theme())
+		if err := confirm.Run(); err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println(ui.Info("Cancelled"))
+			return nil
+		}
+	}
+
+	var tagErr error
+	_ = spinner.New().
+		Title("Updating tags...").
+		Action(func() {
+			if len(remove) > 0 {
+				if tagErr = client.RemoveTags(path, remove); tagErr != nil {
+					return
+				}
+			}
+			if len(set) > 0 {
+				tagErr = client.SetTags(path, set)
+			}
+		}).
+		Run()
+
+	if tagErr != nil {
+		fmt.Println(ui.Error("Failed to update tags"))
+		return fmt.Errorf("failed to update tags: %w", tagErr)
+	}
+
+	fmt.Println(ui.Successf("Tags updated: %s (%d set, %d removed)", path, len(set), len(remove)))
+	return nil
+}
+
+// editTags lets the user add, change and remove tags on a copy of tags until
+// they pick Done. It returns the edited tags, or nil if the user cancels.
+func editTags(path string, tags map[string]string) (map[string]string, error) {
+	edited := make(map[string]string, len(tags))
+	for k, v := range tags {
+		edited[k] = v
+	}
+
+	for {
+		shown := redactTags(edited)
+		keys := make([]string, 0, len(edited))
+		for k := range edited {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		opts := make([]huh.Option[string], 0, len(keys)+2)
+		for _, k := range keys {
+			opts = append(opts, huh.NewOption(k+" = "+shown[k], k))
+		}
+		opts = append(opts,
+			huh.NewOption("+ Add a tag", tagEditAdd),
+			huh.NewOption("✓ Done", tagEditDone))
+
+		var choice string
+		sel := huh.NewSelect[string]().
+			Title("Tags on " + path).
+			Description("Pick a tag to change or remove it").
+			Options(opts...).
+			Value(&choice)
+		sel.WithTheme(ui.Theme())
+		if err := sel.Run(); err != nil {
+			if errors.Is(err, huh.ErrUserAborted) {
+				return nil, nil
+			}
+			return nil, err
+		}
+
+		switch choice {
+		case tagEditDone:
+			return edited, nil
+
+		case tagEditAdd:
+			key, err := promptTagKey(edited)
+			if err != nil {
+				return nil, err
+			}
+			if key == "" {
+				continue
+			}
+			value, ok, err := promptTagValue(key, "")
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				edited[key] = value
+			}
+
+		default:
+			var action string
+			sel := huh.NewSelect[string]().
+				Title(choice).
+				Options(
+					huh.NewOption("Change value", tagEditChange),
+					huh.NewOption("Remove", tagEditRemove),
+					huh.NewOption("Back", tagEditBack)).
+				Value(&action)
+			sel.WithTheme(ui.Theme())
+			if err := sel.Run(); err != nil {
+				if errors.Is(err, huh.ErrUserAborted) {
+					continue
+				}
+				return nil, err
+			}
+
+			switch action {
+			case tagEditChange:
+				current := edited[choice]
+				if shown[choice] != current {
+					current = ""
+				}
+				value, ok, err := promptTagValue(choice, current)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					edited[choice] = value
+				}
+			case tagEditRemove:
+				delete(edited, choice)
+			}
+		}
+	}
+}
+
+// promptTagKey asks for a new tag key. It returns "" if the user goes back.
+func promptTagKey(existing map[string]string) (string, error) {
+	var key string
+	input := huh.NewInput().
+		Title("Tag key").
+		Value(&key).
+		Validate(func(s string) error {
+			s = strings.TrimSpace(s)
+			switch {
+			case s == "":
+				return fmt.Errorf("key cannot be empty")
+			case len(s) > 128:
+				return fmt.Errorf("key is longer than 128 characters")
+			case strings.HasPrefix(strings.ToLower(s), "aws:"):
+				return fmt.Errorf("the aws: prefix is reserved")
+			}
+			if _, ok := existing[s]; ok {
+				return fmt.Errorf("%s is already set; pick it from the list to change it", s)
+			}
+			return nil
+		})
+	input.WithTheme(ui.Theme())
+
+	if err := input.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimSpace(key), nil
+}
+
+// promptTagValue asks for the value of key, starting from current. ok is
+// false if the user goes back.
+func promptTagValue(key, current string) (value string, ok bool, err error) {
+	value = current
+	input := huh.NewInput().
+		Title("Value for " + key).
+		Value(&value).
+		Validate(func(s string) error {
+			if len(s) > 256 {
+				return fmt.Errorf("value is longer than 256 characters")
+			}
+			return nil
+		})
+	input.WithTheme(ui.Theme())
+
+	if err := input.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// tagChanges returns the tags to set (new or changed) and the keys to remove
+// (sorted) to turn before into after
+func tagChanges(before, after map[string]string) (map[string]string, []string) {
+	set := make(map[string]string)
+	for k, v := range after {
+		if old, ok := before[k]; !ok || old != v {
+			set[k] = v
+		}
+	}
+
+	var remove []string
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			remove = append(remove, k)
+		}
+	}
+	sort.Strings(remove)
+	return set, remove
+}
+
+// printTagDiff shows added (+), changed (~) and removed (-) tags in the plan
+// colors, with redact_tags values masked
+func printTagDiff(before, after map[string]string) {
+	shownBefore, shownAfter := redactTags(before), redactTags(after)
+
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	fmt.Println()
+	fmt.Println(ui.SectionHeader("Tag changes"))
+	fmt.Println()
+	for _, k := range sorted {
+		old, hadOld := before[k]
+		v, hasNew := after[k]
+		switch {
+		case !hadOld:
+			fmt.Println("  " + planCreateStyle.Render("+ "+k) + " = " + shownAfter[k])
+		case !hasNew:
+			fmt.Println("  " + planDeleteStyle.Render("- "+k) + " = " + shownBefore[k])
+		case old != v:
+			fmt.Println("  " + planUpdateStyle.Render("~ "+k) + " = " + shownBefore[k] + " → " + shownAfter[k])
+		}
+	}
+	fmt.Println()
+}
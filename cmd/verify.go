@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh/spinner"
+	"github.com/devops-chris/clihq/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var verifyLockfile string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify --lockfile <file>",
+	Short: "Check that secrets are still at the versions in a lockfile",
+	Long: `Check that every secret in a lockfile is still at the version recorded
+there, e.g. to confirm that what's live is what was deployed.
+
+A lockfile is written by list --format path-version: path@version lines, or
+a {path: version} map with --output json or yaml. Paths in it are full paths,
+so prefix and env don't apply, but require_prefix and path_profile_map do.
+Current versions are looked up in batches of 10 with GetParameters; values
+are not decrypted.
+
+A secret whose version differs is reported as changed, one that no longer
+exists as missing. Secrets that exist but aren't in the lockfile are not
+checked. Exits 1 if anything drifted.
+
+Examples:
+  # Record the live versions at deploy time
+  lockr list /myapp/prod --recursive --format path-version > secrets.lock
+
+  # Later: has anything changed since?
+  lockr verify --lockfile secrets.lock
+
+  lockr verify --lockfile secrets.lock --output json`,
+	Args: cobra.NoArgs,
+	RunE: runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVar(&verifyLockfile, "lockfile", "", "lockfile written by list --format path-version (required)")
+	_ = verifyCmd.MarkFlagRequired("lockfile")
+}
+
+// versionDrift is a secret whose current version doesn't match the lockfile
+type versionDrift struct {
+	Name           string `json:"name"`
+	LockedVersion  int64  `json:"locked_version"`
+	CurrentVersion int64  `json:"current_version,omitempty"`
+	Status         string `json:"status"` // changed, missing
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(verifyLockfile)
+	if err != nil {
+		return fmt.Errorf("failed to read lockfile: %w", err)
+	}
+	locked, err := parseLockfile(data)
+	if err != nil {
+		return fmt.Errorf("invalid lockfile %s: %w", verifyLockfile, err)
+	}
+	if len(locked) == 0 {
+		return fmt.Errorf("lockfile %s has no entries", verifyLockfile)
+	}
+
+	names := make([]string, 0, len(locked))
+	for name := range locked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := buildPath(name); err != nil {
+			return err
+		}
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+
+	var current map[string]int64
+	var versionErr error
+	_ = spinner.New().
+		Title("Checking versions...").
+		Action(func() {
+			current, versionErr = client.CurrentVersions(names)
+		}).
+		Run()
+	if versionErr != nil {
+		fmt.Fprintln(statusOut, ui.Error("Failed to check versions"))
+		return fmt.Errorf("failed to check versions: %w", versionErr)
+	}
+
+	drift := []versionDrift{}
+	for _, name := range names {
+		version, ok := current[name]
+		switch {
+		case !ok:
+			drift = append(drift, versionDrift{Name: name, LockedVersion: locked[name], Status: "missing"})
+		case version != locked[name]:
+			drift = append(drift, versionDrift{Name: name, LockedVersion: locked[name], CurrentVersion: version, Status: "changed"})
+		}
+	}
+
+	switch cfg.Output {
+	case "json", "yaml":
+		if err := printStructured(map[string]interface{}{"verified": len(names), "drift": drift}); err != nil {
+			return err
+		}
+	default:
+		fmt.Fprintln(out)
+		if len(drift) == 0 {
+			fmt.Fprintln(out, ui.Successf("All %d secrets match %s", len(names), verifyLockfile))
+		} else {
+			rows := make([][]string, 0, len(drift))
+			for _, d := range drift {
+				now := "-"
+				if d.Status == "changed" {
+					now = fmt.Sprintf("v%d", d.CurrentVersion)
+				}
+				rows = append(rows, []string{ui.Highlight(d.Name), fmt.Sprintf("v%d", d.LockedVersion), now, d.Status})
+			}
+			fmt.Fprintln(out, ui.Table([]string{"Name", "Locked", "Current", "Status"}, rows))
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, ui.Warningf("%d of %d secrets drifted from %s", len(drift), len(names), verifyLockfile))
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(drift) > 0 {
+		return silentExit(cmd, 1)
+	}
+	return nil
+}
+
+// parseLockfile parses a lockfile written by list --format path-version:
+// path@version lines (blank lines and # comments are skipped), or a JSON or
+// YAML {path: version} map
+func parseLockfile(data []byte) (map[string]int64, error) {
+	text := strings.TrimSpace(string(data))
+
+	// SSM names can't contain @, so a map never has one
+	if strings.HasPrefix(text, "{") || !strings.Contains(text, "@") {
+		var locked map[string]int64
+		if err := yaml.Unmarshal(data, &locked); err != nil {
+			return nil, fmt.Errorf("not path@version lines or a {path: version} map: %w", err)
+		}
+		return locked, nil
+	}
+
+	locked := make(map[string]int64)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		at := strings.LastIndex(line, "@")
+		if at <= 0 {
+			return nil, fmt.Errorf("line %d: %q is not path@version", i+1, line)
+		}
+		version, err := strconv.ParseInt(line[at+1:], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("line %d: %q is not path@version", i+1, line)
+		}
+		locked[line[:at]] = version
+	}
+	return locked, nil
+}
@@ -2,21 +2,72 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/spf13/cobra"
 )
 
+var (
+	versionShort bool
+	versionFull  bool
+)
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print version information",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("lockr %s\n", version)
-		fmt.Printf("  commit: %s\n", commit)
-		fmt.Printf("  built:  %s\n", buildDate)
+	Long: `Print version information.
+
+Examples:
+  # Human-readable
+  lockr version
+
+  # Just the version string, for scripts
+  lockr version --short
+
+  # Include Go, platform and AWS SDK versions (for bug reports)
+  lockr version --full
+
+  # Machine-parseable
+  lockr version --output json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if versionShort {
+			fmt.Fprintln(out, version)
+			return nil
+		}
+
+		platform := runtime.GOOS + "/" + runtime.GOARCH
+
+		if cfg.Output != "text" {
+			info := map[string]string{
+				"version":   version,
+				"commit":    commit,
+				"buildDate": buildDate,
+			}
+			if versionFull {
+				info["goVersion"] = runtime.Version()
+				info["platform"] = platform
+				info["awsSdkVersion"] = aws.SDKVersion
+			}
+			return printStructured(info)
+		}
+
+		fmt.Fprintf(out, "lockr %s\n", version)
+		fmt.Fprintf(out, "  commit: %s\n", commit)
+		fmt.Fprintf(out, "  built:  %s\n", buildDate)
+		if versionFull {
+			fmt.Fprintf(out, "  go:     %s\n", runtime.Version())
+			fmt.Fprintf(out, "  os:     %s\n", platform)
+			fmt.Fprintf(out, "  sdk:    aws-sdk-go-v2 %s\n", aws.SDKVersion)
+		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().BoolVar(&versionShort, "short", false, "print only the version string")
+	versionCmd.Flags().BoolVarP(&versionFull, "full", "v", false, "also show Go version, OS/arch and AWS SDK version")
 }
 
@@ -2,56 +2,159 @@ package cmd
 
 import (
 	"bufio"
+	"crypto/rand"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/big"
 	"os"
+	"os/exec"
+	"runtime"
 	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/huh/spinner"
 	"github.com/devops-chris/clihq/ui"
+	"github.com/devops-chris/lockr/internal/schema"
 	"github.com/devops-chris/lockr/internal/ssm"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
 var (
-	writeValue     string
-	writeFile      string
-	writeTags      []string
-	writeOverwrite bool
+	writeValue       string
+	writeValueEnv    string
+	writeFromCommand string
+	writeTrim        bool
+	writeFile        string
+	writeTags        []string
+	writeOverwrite   bool
+	writeReplaceTags bool
+	writeSchema      string
+	writeForceNew    bool
+	writeGenerate    bool
+	writeLength      int
+	writeIfNotExists bool
+	writeConfirm     bool
+	writeNoAutoTags  bool
+	writeNoConfirm   bool
+	writeBatch       string
+	writeAllowEmpty  bool
+	writeRaw         bool
+	writePrintPath   bool
+	writeMergeJSON   []string
 )
 
+// generateAlphabet is the character set for --generate values
+const generateAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
 var writeCmd = &cobra.Command{
-	Use:   "write <path>",
+	Use:   "write [path...]",
 	Short: "Write a secret to SSM Parameter Store",
 	Long: `Write a secret to AWS SSM Parameter Store.
 
-If no value is provided, you'll be prompted to enter it securely.
+Without a path (in a terminal), an interactive path builder lets you drill
+down through existing path segments and type the final name.
+
+If no value is provided, it's read from stdin when stdin is piped or
+redirected, and otherwise you'll be prompted to enter it securely, twice,
+so a typo can't be stored unnoticed (--no-confirm asks only once).
 The value will not appear in your shell history.
 
+Several paths can be given to write the same value to each of them; every
+path is reported as written, unchanged or failed, and a failure doesn't stop
+the remaining writes.
+
+--batch reads a JSON array of {"name", "value", "tags"} entries (from a file,
+or stdin with "-") and writes each one; relative names use prefix/env like
+paths given as arguments. This is meant for programs feeding lockr; for files
+you maintain by hand, see import and apply.
+
+A typed, --value or piped value with leading or trailing whitespace (often a
+paste accident) triggers a warning and, in a terminal, a confirmation; --trim
+strips the whitespace and --raw keeps it without asking.
+
+--merge-json updates fields of a secret holding a JSON object instead of
+replacing the whole value: the current value is read, each key=value (a
+string) or key:=json (any JSON value) is set at its dotted key, creating
+missing objects, and the result is written as a new version. Keys come out
+sorted. The current value must be a valid JSON object.
+
+Empty values are refused unless --allow-empty is given. Note that SSM
+itself rejects empty SecureString values, so a placeholder value is usually
+the better choice.
+
+If the secret already holds the same value, the write is skipped so the
+version doesn't change. Use --force-new-version to write a new version anyway.
+
+With auto_tags enabled in config (LOCKR_AUTO_TAGS=true), every write also
+tags the secret with lockr:last-writer (the caller's ARN) and lockr:written-at
+(UTC timestamp). Tags given with --tag take precedence.
+
 Examples:
   # Interactive (secure prompt)
   lockr write /myapp/prod/db-password
 
+  # Build the path interactively from existing segments
+  lockr write
+
   # With value flag (use carefully - may appear in history)
   lockr write /myapp/prod/api-key --value "sk_live_xxx"
 
+  # From an environment variable (keeps the value out of argv and history)
+  lockr write /myapp/prod/api-key --value-env API_KEY
+
+  # From another command's output (runs via the shell; stored as printed,
+  # trailing newline included, unless --trim)
+  lockr write /myapp/prod/jwt-secret --from-command 'openssl rand -base64 32' --trim
+
   # From file (great for certs, keys, JSON)
   lockr write /myapp/prod/tls-cert --file ./cert.pem
 
-  # From stdin (for piping)
-  cat cert.pem | lockr write /myapp/prod/tls-cert --value -
+  # From stdin (for piping; --value - is optional when stdin isn't a terminal)
+  cat cert.pem | lockr write /myapp/prod/tls-cert
+  lockr write /myapp/prod/tls-cert < cert.pem
   echo "myvalue" | lockr write /myapp/prod/key --value -
 
+  # Same value at several paths (e.g. during a namespace migration)
+  lockr write --value-env SECRET /svc-a/prod/key /svc-b/prod/key /svc-c/prod/key
+
+  # Many secrets from another system's JSON output
+  fetch-secrets | lockr write --batch - --output json
+
   # With tags
   lockr write /myapp/prod/api-key --tag owner=platform --tag env=prod
 
+  # Make the tags exactly match (removes any other existing tags)
+  lockr write /myapp/prod/api-key --tag owner=platform --replace-tags
+
+  # Skip the auto_tags provenance tags for one write
+  lockr write /myapp/prod/api-key --value-env API_KEY --no-auto-tags
+
+  # Bump the version even if the value hasn't changed
+  lockr write /myapp/prod/api-key --value-env API_KEY --force-new-version
+
+  # Change one field of a JSON secret (repeatable; := sets a JSON value)
+  lockr write /myapp/config --merge-json db.password=newpass --merge-json db.port:=5432
+
+  # Validate a JSON value against a JSON Schema before storing
+  lockr write /myapp/prod/config --file ./config.json --schema ./config.schema.json
+
+  # Read back after writing and fail if the value or version doesn't match
+  lockr write /myapp/prod/api-key --value-env API_KEY --confirm-write
+
+  # Ensure a secret exists: generate a random value only if it's absent
+  lockr write /myapp/prod/jwt-secret --generate --length 48 --if-not-exists
+
+  # Capture the resolved path (after prefix/env) in a script
+  P=$(lockr write db/password --value-env DB_PASSWORD --print-path)
+
   # With prefix and env configured
   export LOCKR_PREFIX=/infra/saas
   export LOCKR_ENV=prod
   lockr write stripe/secret-key
   # Creates: /infra/saas/prod/stripe/secret-key`,
-	Args: cobra.ExactArgs(1),
+	Args: cobra.ArbitraryArgs,
 	RunE: runWrite,
 }
 
@@ -59,17 +162,104 @@ func init() {
 	rootCmd.AddCommand(writeCmd)
 
 	writeCmd.Flags().StringVarP(&writeValue, "value", "v", "", "secret value (use '-' to read from stdin)")
+	writeCmd.Flags().StringVar(&writeValueEnv, "value-env", "", "read secret value from the named environment variable")
+	writeCmd.Flags().StringVar(&writeFromCommand, "from-command", "", "use the stdout of a shell command as the secret value")
 	writeCmd.Flags().StringVarP(&writeFile, "file", "f", "", "read secret value from file")
+	writeCmd.Flags().BoolVar(&writeTrim, "trim", false, "trim leading and trailing whitespace from the value")
+	writeCmd.Flags().BoolVar(&writePrintPath, "print-path", false, "print only the resolved path(s) written, one per line (for scripts)")
+	writeCmd.Flags().BoolVar(&writeRaw, "raw", false, "keep leading and trailing whitespace without asking")
 	writeCmd.Flags().StringSliceVarP(&writeTags, "tag", "t", nil, "tags in key=value format (can be repeated)")
 	writeCmd.Flags().BoolVar(&writeOverwrite, "overwrite", true, "overwrite existing secret")
+	writeCmd.Flags().BoolVar(&writeReplaceTags, "replace-tags", false, "replace all existing tags instead of merging")
+	writeCmd.Flags().BoolVar(&writeForceNew, "force-new-version", false, "write a new version even if the value is unchanged")
+	writeCmd.Flags().StringVar(&writeSchema, "schema", "", "validate the (JSON) value against a JSON Schema file before writing")
+	writeCmd.Flags().BoolVar(&writeGenerate, "generate", false, "generate a random alphanumeric value (never printed)")
+	writeCmd.Flags().IntVar(&writeLength, "length", 32, "length of the --generate value")
+	writeCmd.Flags().BoolVar(&writeIfNotExists, "if-not-exists", false, "only write if the secret doesn't exist yet")
+	writeCmd.Flags().BoolVar(&writeNoConfirm, "no-confirm", false, "don't ask for the value a second time at the secure prompt")
+	writeCmd.Flags().BoolVar(&writeNoAutoTags, "no-auto-tags", false, "don't add the auto_tags provenance tags to this write")
+	writeCmd.Flags().BoolVar(&writeAllowEmpty, "allow-empty", false, "allow writing an empty value (SSM rejects empty SecureStrings)")
+	writeCmd.Flags().StringArrayVar(&writeMergeJSON, "merge-json", nil, "set a field in the current JSON value: key=value or key:=json (dotted keys, repeatable)")
+	writeCmd.Flags().StringVar(&writeBatch, "batch", "", "write a JSON array of {name, value, tags} entries from a file ('-' for stdin)")
+	writeCmd.Flags().BoolVar(&writeConfirm, "confirm-write", false, "read the secret back after writing and fail unless the value and version match")
 }
 
 func runWrite(cmd *cobra.Command, args []string) error {
-	path := buildPath(args[0])
+	if writeBatch != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("--batch cannot be combined with path arguments")
+		}
+		if writeGenerate || writeFile != "" || writeValueEnv != "" || writeFromCommand != "" || writeValue != "" || len(writeMergeJSON) > 0 {
+			return fmt.Errorf("--batch cannot be combined with another value source")
+		}
+		return runWriteBatch(writeBatch)
+	}
+
+	var paths []string
+	if len(args) == 0 {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("a path is required when not running in a terminal")
+		}
+		built, err := interactivePathBuilder()
+		if err != nil {
+			return err
+		}
+		if built == "" {
+			return nil // User cancelled
+		}
+		// Recorded like a typed path, so path_profile_map still applies
+		if built, err = buildPath(built); err != nil {
+			return err
+		}
+		paths = []string{built}
+	} else {
+		for _, arg := range args {
+			path, err := buildPath(arg)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, path)
+		}
+	}
 	var value string
+	// typed is set for values that were entered or piped, where stray
+	// whitespace is usually a paste accident rather than intended
+	var typed bool
 
-	// Determine value source: file > value flag > stdin prompt
+	if writeGenerate && (writeFile != "" || writeValueEnv != "" || writeFromCommand != "" || writeValue != "") {
+		return fmt.Errorf("--generate cannot be combined with another value source")
+	}
+	if len(writeMergeJSON) > 0 {
+		if writeGenerate || writeFile != "" || writeValueEnv != "" || writeFromCommand != "" || writeValue != "" {
+			return fmt.Errorf("--merge-json cannot be combined with another value source")
+		}
+		if len(paths) > 1 {
+			return fmt.Errorf("--merge-json takes a single path")
+		}
+	}
+
+	// Determine value source: merge > generate > file > env var > command >
+	// value flag > piped stdin > secure prompt
 	switch {
+	case len(writeMergeJSON) > 0:
+		client, err := newClient(cfg.Region)
+		if err != nil {
+			return fmt.Errorf("failed to create SSM client: %w", err)
+		}
+		merged, err := mergeJSONValue(client, paths[0], writeMergeJSON)
+		if err != nil {
+			fmt.Println(ui.Error("Failed to merge JSON"))
+			return err
+		}
+		value = merged
+
+	case writeGenerate:
+		v, err := generateValue(writeLength)
+		if err != nil {
+			return err
+		}
+		value = v
+
 	case writeFile != "":
 		// Read from file
 		data, err := os.ReadFile(writeFile)
@@ -79,17 +269,38 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		}
 		value = string(data)
 
-	case writeValue == "-":
-		// Read from stdin (for piping)
-		data, err := readStdin()
+	case writeValueEnv != "":
+		// Read from environment variable
+		v, ok := os.LookupEnv(writeValueEnv)
+		if !ok {
+			fmt.Println(ui.Errorf("Environment variable not set: %s", writeValueEnv))
+			return fmt.Errorf("environment variable %s is not set", writeValueEnv)
+		}
+		value = v
+
+	case writeFromCommand != "":
+		// Capture a command's output
+		data, err := runValueCommand(writeFromCommand)
 		if err != nil {
-			return fmt.Errorf("failed to read from stdin: %w", err)
+			fmt.Println(ui.Error("Command failed"))
+			return fmt.Errorf("failed to run command: %w", err)
 		}
 		value = data
 
-	case writeValue != "":
+	case cmd.Flags().Changed("value") && writeValue != "-":
 		// Use provided value
 		value = writeValue
+		typed = true
+
+	case writeValue == "-", !term.IsTerminal(int(os.Stdin.Fd())):
+		// Read from stdin: explicitly with --value -, or whenever stdin is
+		// piped or redirected
+		data, err := readStdin()
+		if err != nil {
+			return fmt.Errorf("failed to read from stdin: %w", err)
+		}
+		value = data
+		typed = true
 
 	default:
 		// Interactive prompt
@@ -98,44 +309,140 @@ func runWrite(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("failed to read value: %w", err)
 		}
+
+		// Nothing is echoed, so ask twice to catch typos (like passwd)
+		if !writeNoConfirm {
+			again, err := promptSecureValue("Confirm secret value")
+			if err != nil {
+				return fmt.Errorf("failed to read value: %w", err)
+			}
+			if again != value {
+				fmt.Println(ui.Error("Values do not match"))
+				return fmt.Errorf("values do not match, nothing written")
+			}
+		}
+		typed = true
+	}
+
+	if writeTrim && writeRaw {
+		return fmt.Errorf("--trim and --raw are mutually exclusive")
 	}
 
-	if value == "" {
+	if writeTrim {
+		value = strings.TrimSpace(value)
+	} else if !writeRaw && typed && value != strings.TrimSpace(value) {
+		ok, err := confirmWhitespace()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println(ui.Info("Cancelled"))
+			return nil
+		}
+	}
+
+	if value == "" && !writeAllowEmpty {
 		fmt.Println(ui.Error("Value cannot be empty"))
-		return fmt.Errorf("value cannot be empty")
+		return fmt.Errorf("value cannot be empty (use --allow-empty to store an empty string)")
 	}
 
-	// Parse tags
-	tags := make(map[string]string)
-	for _, tag := range writeTags {
-		parts := strings.SplitN(tag, "=", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid tag format: %s (expected key=value)", tag)
+	if writeSchema != "" {
+		problems, err := schema.Validate(writeSchema, value)
+		if err != nil {
+			fmt.Println(ui.Error("Schema validation failed"))
+			return fmt.Errorf("schema validation failed: %w", err)
+		}
+		if len(problems) > 0 {
+			fmt.Println(ui.Error("Value does not match schema"))
+			for _, p := range problems {
+				fmt.Println("  " + p)
+			}
+			return fmt.Errorf("value does not match schema %s (%d error(s))", writeSchema, len(problems))
 		}
-		tags[parts[0]] = parts[1]
 	}
 
-	client, err := ssm.NewClient(cfg.Region)
+	tags, err := parseTags(writeTags)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(cfg.Region)
 	if err != nil {
 		return fmt.Errorf("failed to create SSM client: %w", err)
 	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	// Provenance tags only go on writes; an unchanged value keeps just the
+	// user's tags
+	auto := newAutoTagger(client, writeNoAutoTags)
+
+	if len(paths) > 1 {
+		return writeFanOut(client, paths, value, tags, auto)
+	}
+	path := paths[0]
 
+	var status, keyID string
 	var writeErr error
 	_ = spinner.New().
 		Title("Writing secret...").
 		Action(func() {
-			writeErr = client.WriteSecret(path, value, tags, writeOverwrite, cfg.KMSKey)
+			status, writeErr = writeToPath(client, path, value, tags, auto)
+			if writeErr == nil && status == "written" {
+				// Best-effort: show which KMS key encrypted the new version
+				if meta, err := client.DescribeSecret(path); err == nil {
+					keyID = meta.KeyID
+				}
+			}
 		}).
 		Run()
 
+	switch {
+	case writeErr != nil:
+		emitMetrics(client, "write", 0, 1)
+	case status == "written":
+		emitMetrics(client, "write", 1, 0)
+	}
+
 	if writeErr != nil {
 		fmt.Println(ui.Error("Failed to write secret"))
 		return fmt.Errorf("failed to write secret: %w", writeErr)
 	}
 
-	fmt.Println(ui.Success("Secret written successfully"))
-	fmt.Println()
-	fmt.Println(ui.Subtle("Created: ") + ui.Highlight(path))
+	if writePrintPath {
+		fmt.Fprintln(out, path)
+		return nil
+	}
+
+	if cfg.Output != "text" {
+		output := map[string]interface{}{"path": path, "status": status}
+		if keyID != "" {
+			output["kms_key_id"] = keyID
+		}
+		return printStructured(output)
+	}
+
+	switch status {
+	case "exists":
+		fmt.Println(ui.Info("Secret already exists, left unchanged"))
+		fmt.Println()
+		fmt.Println(ui.Subtle("Path: ") + ui.Highlight(path))
+		fmt.Println()
+		return nil
+	case "unchanged":
+		fmt.Println(ui.Info("Value unchanged, no new version written (use --force-new-version to force one)"))
+		fmt.Println()
+		fmt.Println(ui.Subtle("Path: ") + ui.Highlight(path))
+	default:
+		fmt.Println(ui.Success("Secret written successfully"))
+		fmt.Println()
+		fmt.Println(ui.Subtle("Created: ") + ui.Highlight(path))
+		if keyID != "" {
+			fmt.Println(ui.Subtle("KMS key: ") + keyID)
+		}
+		tags = auto.tags(tags)
+	}
 
 	if len(tags) > 0 {
 		fmt.Println()
@@ -150,12 +457,341 @@ func runWrite(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func buildPath(input string) string {
-	// If input already starts with /, use as-is
-	if strings.HasPrefix(input, "/") {
-		return input
+// writeToPath writes value to one path honoring the write flags. It returns
+// "written", "unchanged" (value already stored, only tags updated) or
+// "exists" (--if-not-exists and the secret was already there). tags are the
+// user's tags; auto adds the auto_tags to them when a new version is written.
+func writeToPath(client *ssm.Client, path, value string, tags map[string]string, auto *autoTagger) (status string, err error) {
+	defer func() {
+		if ssm.IsEmptyValueRejected(err) {
+			err = fmt.Errorf("SSM does not accept empty SecureString values; store a placeholder such as \"-\" or \"none\" instead: %w", err)
+		}
+	}()
+
+	if writeConfirm {
+		prevVersion, err := currentVersion(client, path)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			if err == nil && status == "written" {
+				err = confirmWrite(client, path, value, prevVersion)
+			}
+		}()
+	}
+
+	if writeIfNotExists {
+		// A create-only write is atomic: SSM rejects it if the
+		// parameter already exists
+		err = client.WriteSecret(path, value, auto.tags(tags), false, cfg.KMSKey)
+		if ssm.IsAlreadyExists(err) {
+			return "exists", nil
+		}
+		return "written", err
+	}
+
+	// Skip the write when the value is already stored (best-effort:
+	// if we can't read the current value, just write)
+	if writeOverwrite && !writeForceNew {
+		if unchanged, _ := client.Unchanged(path, value); unchanged {
+			switch {
+			case writeReplaceTags:
+				// Nothing is written, so the provenance of the last real
+				// write stays
+				err = client.ReplaceTags(path, tags, lastWriterTag, writtenAtTag)
+			case len(tags) > 0:
+				err = client.SetTags(path, tags)
+			}
+			return "unchanged", err
+		}
 	}
 
+	if writeReplaceTags {
+		// Write the value first, then make the tags match exactly
+		if err := client.WriteSecret(path, value, nil, writeOverwrite, cfg.KMSKey); err != nil {
+			return "", err
+		}
+		return "written", client.ReplaceTags(path, auto.tags(tags))
+	}
+	return "written", client.WriteSecret(path, value, auto.tags(tags), writeOverwrite, cfg.KMSKey)
+}
+
+// writeResult is the outcome of writing one path in a fan-out or --batch
+// write (or a rekey), as printed by --output json
+type writeResult struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // written, unchanged, exists, rekeyed, copied, failed
+	Error  string `json:"error,omitempty"`
+}
+
+// writeFanOut writes the same value to several paths, continuing past
+// failures and reporting each path's outcome
+func writeFanOut(client *ssm.Client, paths []string, value string, tags map[string]string, auto *autoTagger) error {
+	results := make([]writeResult, len(paths))
+	_ = spinner.New().
+		Title(fmt.Sprintf("Writing %d secrets...", len(paths))).
+		Action(func() {
+			for i, path := range paths {
+				status, err := writeToPath(client, path, value, tags, auto)
+				results[i] = writeResult{Path: path, Status: status}
+				if err != nil {
+					results[i] = writeResult{Path: path, Status: "failed", Error: err.Error()}
+				}
+			}
+		}).
+		Run()
+
+	succeeded, failed := resultCounts(results)
+	emitMetrics(client, "write", succeeded, failed)
+
+	return printWriteResults(results)
+}
+
+// printWriteResults reports the outcome of each write in a multi-secret write
+// and fails if any of them did
+func printWriteResults(results []writeResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Status == "failed" {
+			failed++
+		}
+	}
+
+	switch {
+	case writePrintPath:
+		for _, r := range results {
+			if r.Status == "failed" {
+				fmt.Fprintln(os.Stderr, ui.Errorf("Failed to write %s: %s", r.Path, r.Error))
+				continue
+			}
+			fmt.Fprintln(out, r.Path)
+		}
+	case cfg.Output == "json", cfg.Output == "yaml":
+		if err := printStructured(results); err != nil {
+			return err
+		}
+	default:
+		fmt.Println()
+		for _, r := range results {
+			switch r.Status {
+			case "written":
+				fmt.Println(ui.Successf("Written: %s", r.Path))
+			case "unchanged":
+				fmt.Println(ui.Infof("Unchanged: %s", r.Path))
+			case "exists":
+				fmt.Println(ui.Infof("Already exists: %s", r.Path))
+			default:
+				fmt.Println(ui.Errorf("Failed to write %s: %s", r.Path, r.Error))
+			}
+		}
+		fmt.Println()
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to write %d of %d secrets", failed, len(results))
+	}
+	return nil
+}
+
+// batchEntry is one secret in a --batch JSON array
+type batchEntry struct {
+	Name  string            `json:"name"`
+	Value string            `json:"value"`
+	Tags  map[string]string `json:"tags,omitempty"`
+}
+
+// runWriteBatch writes every entry of the JSON array in source ("-" for
+// stdin), continuing past failures and reporting each entry's outcome
+func runWriteBatch(source string) error {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read batch: %w", err)
+	}
+
+	var entries []batchEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("invalid batch (expected a JSON array of {name, value, tags}): %w", err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("batch is empty")
+	}
+	for i, e := range entries {
+		if e.Name == "" {
+			return fmt.Errorf("batch entry %d has no name", i+1)
+		}
+	}
+
+	flagTags, err := parseTags(writeTags)
+	if err != nil {
+		return err
+	}
+
+	// Resolve every path first: they pick the client's profile
+	paths := make([]string, len(entries))
+	for i, e := range entries {
+		if paths[i], err = buildPath(e.Name); err != nil {
+			return err
+		}
+	}
+
+	client, err := newClient(cfg.Region)
+	if err != nil {
+		return fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if err := confirmRegion(client); err != nil {
+		return err
+	}
+
+	// Shared so the caller is resolved once rather than per entry
+	auto := newAutoTagger(client, writeNoAutoTags)
+
+	results := make([]writeResult, len(entries))
+	_ = spinner.New().
+		Title(fmt.Sprintf("Writing %d secrets...", len(entries))).
+		Action(func() {
+			for i, e := range entries {
+				path := paths[i]
+				value := e.Value
+				if writeTrim {
+					value = strings.TrimSpace(value)
+				}
+				if value == "" && !writeAllowEmpty {
+					results[i] = writeResult{Path: path, Status: "failed", Error: "value cannot be empty"}
+					continue
+				}
+
+				// Entry tags override --tag; auto_tags only fill in gaps
+				tags := map[string]string{}
+				for k, v := range flagTags {
+					tags[k] = v
+				}
+				for k, v := range e.Tags {
+					tags[k] = v
+				}
+
+				status, err := writeToPath(client, path, value, tags, auto)
+				results[i] = writeResult{Path: path, Status: status}
+				if err != nil {
+					results[i] = writeResult{Path: path, Status: "failed", Error: err.Error()}
+				}
+			}
+		}).
+		Run()
+
+	succeeded, failed := resultCounts(results)
+	emitMetrics(client, "write", succeeded, failed)
+
+	return printWriteResults(results)
+}
+
+// confirmWhitespace warns that the value has leading or trailing whitespace
+// and, in a terminal, asks whether to write it anyway. Without a terminal (or
+// with assume_yes) it only warns.
+func confirmWhitespace() (bool, error) {
+	fmt.Fprintln(statusOut, ui.Warning("Value has leading or trailing whitespace (use --trim to strip it or --raw to keep it without asking)"))
+	if cfg.AssumeYes || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true, nil
+	}
+
+	var confirmed bool
+	confirm := huh.NewConfirm().
+		Title("Write the value with its whitespace?").
+		Value(&confirmed)
+	confirm.WithTheme(ui.Theme())
+	if err := confirm.Run(); err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// currentVersion returns the latest version of path, or 0 if it doesn't exist
+func currentVersion(client *ssm.Client, path string) (int64, error) {
+	secret, err := client.ReadSecret(path)
+	if err != nil {
+		if ssm.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return secret.Version, nil
+}
+
+// confirmWrite reads path back and checks it holds value at a version newer
+// than prevVersion (--confirm-write)
+func confirmWrite(client *ssm.Client, path, value string, prevVersion int64) error {
+	secret, err := client.ReadSecret(path)
+	if err != nil {
+		return fmt.Errorf("write confirmation failed: %w", err)
+	}
+	if secret.Value != value {
+		return fmt.Errorf("write confirmation failed: stored value differs from the value written (version %d)", secret.Version)
+	}
+	if secret.Version <= prevVersion {
+		return fmt.Errorf("write confirmation failed: version did not increase (was %d, now %d)", prevVersion, secret.Version)
+	}
+	return nil
+}
+
+// generateValue returns a cryptographically random alphanumeric string
+func generateValue(length int) (string, error) {
+	if length < 1 {
+		return "", fmt.Errorf("--length must be at least 1")
+	}
+
+	limit := big.NewInt(int64(len(generateAlphabet)))
+	b := make([]byte, length)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate value: %w", err)
+		}
+		b[i] = generateAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// parseTags parses key=value tag arguments into a map
+func parseTags(args []string) (map[string]string, error) {
+	tags := make(map[string]string)
+	for _, tag := range args {
+		parts := strings.SplitN(tag, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid tag format: %s (expected key=value)", tag)
+		}
+		tags[parts[0]] = parts[1]
+	}
+	return tags, nil
+}
+
+// buildPath resolves a path argument: relative input is placed under the
+// configured prefix/env, and the result is checked against require_prefix.
+// It is recorded for newClient's profile choice, so resolve every path before
+// creating the client.
+func buildPath(input string) (string, error) {
+	path := input
+	// If input doesn't start with /, place it under prefix/env
+	if !strings.HasPrefix(input, "/") {
+		path = pathBase() + input
+	}
+
+	if err := checkRequiredPrefix(path); err != nil {
+		return "", err
+	}
+	if err := recordPath(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// pathBase returns the configured prefix/env that relative paths are placed
+// under, always ending in "/" (just "/" when neither is set)
+func pathBase() string {
 	var parts []string
 
 	// Add prefix if configured
@@ -169,10 +805,10 @@ func buildPath(input string) string {
 		parts = append(parts, cfg.Env)
 	}
 
-	// Add the input path
-	parts = append(parts, input)
-
-	return "/" + strings.Join(parts, "/")
+	if len(parts) == 0 {
+		return "/"
+	}
+	return "/" + strings.Join(parts, "/") + "/"
 }
 
 func promptSecureValue(title string) (string, error) {
@@ -195,6 +831,35 @@ func promptSecureValue(title string) (string, error) {
 	return value, nil
 }
 
+// runValueCommand runs command via the shell and returns its stdout exactly
+// as printed, trailing newline included; --trim strips it. stderr is passed
+// through so the user sees the command's errors.
+func runValueCommand(command string) (string, error) {
+	c := shellCommand(command)
+	c.Stdin = os.Stdin
+	return commandValue(c)
+}
+
+// shellCommand builds a command that runs via the platform shell
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// commandValue runs c and returns its stdout unchanged. stderr is passed
+// through.
+func commandValue(c *exec.Cmd) (string, error) {
+	c.Stderr = os.Stderr
+
+	stdout, err := c.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(stdout), nil
+}
+
 func readStdin() (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 	var lines []string
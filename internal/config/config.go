@@ -1,24 +1,35 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/viper"
 )
 
+// ProjectFile is the project-local config file, looked up from the current
+// directory upwards
+const ProjectFile = ".lockr.yaml"
+
 // Config holds all configuration options
-// Precedence: CLI flags > ENV vars > Config file > Defaults
+// Precedence: CLI flags > ENV vars > Project file (.lockr.yaml) > User config file > Defaults
 type Config struct {
 	// Prefix is prepended to paths that don't start with /
 	// ENV: LOCKR_PREFIX
 	Prefix string `mapstructure:"prefix"`
 
+	// RequirePrefix rejects paths outside Prefix (e.g. a mistyped absolute
+	// path) unless --allow-absolute is given
+	// ENV: LOCKR_REQUIRE_PREFIX
+	RequirePrefix bool `mapstructure:"require_prefix"`
+
 	// Env is the environment (prod, staging, dev) added to the path
 	// ENV: LOCKR_ENV
 	Env string `mapstructure:"env"`
 
-	// Output format: text, json
+	// Output format: text, json, yaml
 	// ENV: LOCKR_OUTPUT
 	Output string `mapstructure:"output"`
 
@@ -30,6 +41,74 @@ type Config struct {
 	// Region overrides the AWS region
 	// ENV: LOCKR_REGION (or AWS_REGION)
 	Region string `mapstructure:"region"`
+
+	// ExpectedRegion makes mutating commands ask for confirmation when the
+	// resolved region differs from it
+	// ENV: LOCKR_EXPECTED_REGION
+	ExpectedRegion string `mapstructure:"expected_region"`
+
+	// ConfirmWord is the word destructive commands ask you to type to confirm
+	// (empty = the path being deleted or moved, or the number of secrets)
+	// ENV: LOCKR_CONFIRM_WORD
+	ConfirmWord string `mapstructure:"confirm_word"`
+
+	// AssumeYes skips every confirmation prompt, as if --force or
+	// --auto-approve were given. Dangerous: only for trusted automation.
+	// ENV: LOCKR_ASSUME_YES
+	AssumeYes bool `mapstructure:"assume_yes"`
+
+	// RateLimit caps SSM API requests per second (0 = unlimited)
+	// ENV: LOCKR_RATE_LIMIT
+	RateLimit float64 `mapstructure:"rate_limit"`
+
+	// EmitMetrics publishes a CloudWatch metric for each write/delete
+	// ENV: LOCKR_EMIT_METRICS
+	EmitMetrics bool `mapstructure:"emit_metrics"`
+
+	// MetricsNamespace is the CloudWatch namespace for emitted metrics
+	// ENV: LOCKR_METRICS_NAMESPACE
+	// Default: lockr
+	MetricsNamespace string `mapstructure:"metrics_namespace"`
+
+	// ConfirmReveal asks before showing a value picked in an interactive flow
+	// ENV: LOCKR_CONFIRM_REVEAL
+	ConfirmReveal bool `mapstructure:"confirm_reveal"`
+
+	// Redact masks every secret value in output (for demos and recordings)
+	// ENV: LOCKR_REDACT
+	Redact bool `mapstructure:"redact"`
+
+	// RedactTags lists tag keys whose values are shown as *** in output
+	// ENV: LOCKR_REDACT_TAGS (comma-separated)
+	RedactTags []string `mapstructure:"redact_tags"`
+
+	// AutoTags adds lockr:last-writer and lockr:written-at tags on every write
+	// ENV: LOCKR_AUTO_TAGS
+	AutoTags bool `mapstructure:"auto_tags"`
+
+	// AWSConfigFile overrides the AWS shared config file (~/.aws/config)
+	// ENV: LOCKR_AWS_CONFIG_FILE
+	AWSConfigFile string `mapstructure:"aws_config_file"`
+
+	// AWSCredentialsFile overrides the AWS shared credentials file (~/.aws/credentials)
+	// ENV: LOCKR_AWS_CREDENTIALS_FILE
+	AWSCredentialsFile string `mapstructure:"aws_credentials_file"`
+
+	// CacheCredentials caches assumed-role sessions on disk between runs
+	// ENV: LOCKR_CACHE_CREDENTIALS
+	CacheCredentials bool `mapstructure:"cache_credentials"`
+
+	// RoleARNs are roles assumed in order after loading the base credentials
+	// ENV: LOCKR_ROLE_ARN (comma-separated)
+	RoleARNs []string `mapstructure:"role_arn"`
+
+	// ExternalIDs are the external IDs for RoleARNs, by position ("" for none)
+	// ENV: LOCKR_EXTERNAL_ID (comma-separated)
+	ExternalIDs []string `mapstructure:"external_id"`
+
+	// PathProfileMap selects the AWS profile from the secret path: glob
+	// pattern (matching the path or a parent path) -> profile name
+	PathProfileMap map[string]string `mapstructure:"path_profile_map"`
 }
 
 // DefaultConfig returns configuration with sane defaults
@@ -40,27 +119,58 @@ func DefaultConfig() *Config {
 		Output: "text",
 		KMSKey: "alias/aws/ssm", // AWS managed key - just works
 		Region: "",             // Use AWS SDK default
+
+		MetricsNamespace: "lockr",
 	}
 }
 
-// Load reads configuration from file and environment
-func Load(configFile string) *Config {
+// Load reads configuration from file and environment. With noConfigFile,
+// no config file is read (not even configFile) so only env vars and defaults
+// apply.
+//
+// A config file that exists but can't be read or parsed, or values that don't
+// fit their fields, are reported as an error. The returned Config is always
+// usable and holds whatever could be loaded (at least defaults and env vars).
+func Load(configFile string, noConfigFile bool) (*Config, error) {
 	cfg := DefaultConfig()
 
 	v := viper.New()
 
 	// Set defaults
 	v.SetDefault("prefix", cfg.Prefix)
+	v.SetDefault("require_prefix", cfg.RequirePrefix)
 	v.SetDefault("env", cfg.Env)
 	v.SetDefault("output", cfg.Output)
 	v.SetDefault("kms_key", cfg.KMSKey)
 	v.SetDefault("region", cfg.Region)
+	v.SetDefault("expected_region", cfg.ExpectedRegion)
+	v.SetDefault("confirm_word", cfg.ConfirmWord)
+	v.SetDefault("assume_yes", cfg.AssumeYes)
+	v.SetDefault("rate_limit", cfg.RateLimit)
+	v.SetDefault("emit_metrics", cfg.EmitMetrics)
+	v.SetDefault("metrics_namespace", cfg.MetricsNamespace)
+	v.SetDefault("confirm_reveal", cfg.ConfirmReveal)
+	v.SetDefault("redact", cfg.Redact)
+	v.SetDefault("redact_tags", cfg.RedactTags)
+	v.SetDefault("auto_tags", cfg.AutoTags)
+	v.SetDefault("aws_config_file", cfg.AWSConfigFile)
+	v.SetDefault("aws_credentials_file", cfg.AWSCredentialsFile)
+	v.SetDefault("cache_credentials", cfg.CacheCredentials)
+	v.SetDefault("role_arn", cfg.RoleARNs)
+	v.SetDefault("external_id", cfg.ExternalIDs)
+	v.SetDefault("path_profile_map", cfg.PathProfileMap)
 
 	// Environment variables
 	v.SetEnvPrefix("LOCKR")
 	v.AutomaticEnv()
 
 	// Config file
+	if noConfigFile {
+		if err := v.Unmarshal(cfg); err != nil {
+			return cfg, fmt.Errorf("invalid configuration: %w", err)
+		}
+		return cfg, nil
+	}
 	if configFile != "" {
 		v.SetConfigFile(configFile)
 	} else {
@@ -75,11 +185,77 @@ func Load(configFile string) *Config {
 		v.SetConfigType("yaml")
 	}
 
-	// Read config file (ignore if not found)
-	_ = v.ReadInConfig()
+	// Read config file (a discovered file not existing is fine)
+	var loadErr error
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			loadErr = fmt.Errorf("failed to read config file %s: %w", v.ConfigFileUsed(), err)
+		}
+	}
+
+	// Project file overrides the user config key by key, unless a config
+	// file was given explicitly
+	if project := findProjectFile(); project != "" && configFile == "" {
+		if err := mergeProjectFile(v, project); err != nil && loadErr == nil {
+			loadErr = err
+		}
+	}
 
 	// Unmarshal into struct
-	_ = v.Unmarshal(cfg)
+	if err := v.Unmarshal(cfg); err != nil && loadErr == nil {
+		loadErr = fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, loadErr
+}
+
+// projectKeys are the settings a project file may set. Anything that
+// skips prompts, picks credentials or roles, or changes where secrets go
+// beyond their path has to come from the user's own config, env or flags,
+// since a project file is picked up from whatever repository you're in.
+var projectKeys = map[string]bool{
+	"prefix":         true,
+	"env":            true,
+	"require_prefix": true,
+	"output":         true,
+}
 
-	return cfg
+// mergeProjectFile merges the project file at path into v, refusing keys
+// that aren't in projectKeys
+func mergeProjectFile(v *viper.Viper, path string) error {
+	pv := viper.New()
+	pv.SetConfigFile(path)
+	pv.SetConfigType("yaml")
+	if err := pv.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	settings := pv.AllSettings()
+	for key := range settings {
+		if !projectKeys[key] {
+			return fmt.Errorf("%s sets %s, which a project file can't set (allowed: prefix, env, require_prefix, output)", path, key)
+		}
+	}
+	return v.MergeConfigMap(settings)
+}
+
+// findProjectFile returns the nearest .lockr.yaml in the current directory or
+// one of its parents, or "" if there is none
+func findProjectFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
 }
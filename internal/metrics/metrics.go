@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// Emitter publishes lockr usage metrics to CloudWatch
+type Emitter struct {
+	client    *cloudwatch.Client
+	region    string
+	namespace string
+}
+
+// NewEmitter creates an Emitter using the given AWS config's credentials and region
+func NewEmitter(cfg aws.Config, namespace string) *Emitter {
+	return &Emitter{
+		client:    cloudwatch.NewFromConfig(cfg),
+		region:    cfg.Region,
+		namespace: namespace,
+	}
+}
+
+// Emit records count operations for command with the given outcome
+// (e.g. "success", "failure") as a Count metric named Operations
+func (e *Emitter) Emit(ctx context.Context, command, outcome string, count int) error {
+	if e.region == "" {
+		return fmt.Errorf("no AWS region configured")
+	}
+
+	_, err := e.client.PutMetricData(ctx, &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(e.namespace),
+		MetricData: []types.MetricDatum{{
+			MetricName: aws.String("Operations"),
+			Value:      aws.Float64(float64(count)),
+			Unit:       types.StandardUnitCount,
+			Dimensions: []types.Dimension{
+				{Name: aws.String("Command"), Value: aws.String(command)},
+				{Name: aws.String("Outcome"), Value: aws.String(outcome)},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("PutMetricData failed: %w", err)
+	}
+	return nil
+}
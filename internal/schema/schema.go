@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// Validate checks that value is valid JSON conforming to the JSON Schema in
+// schemaFile. It returns one human-readable message per violation, or nil if
+// the value is valid. A non-nil error means the schema or value couldn't be
+// processed at all.
+func Validate(schemaFile, value string) ([]string, error) {
+	path, err := filepath.Abs(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sch, err := jsonschema.Compile(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", schemaFile, err)
+	}
+
+	var doc interface{}
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("value is not valid JSON: %w", err)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("value is not valid JSON: unexpected data after top-level value")
+	}
+
+	err = sch.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return nil, err
+	}
+
+	var problems []string
+	collectLeaves(ve, &problems)
+	return problems, nil
+}
+
+// collectLeaves walks the validation error tree and records the leaf errors,
+// which carry the specific reason a value failed
+func collectLeaves(ve *jsonschema.ValidationError, out *[]string) {
+	if len(ve.Causes) == 0 {
+		loc := ve.InstanceLocation
+		if loc == "" {
+			loc = "(root)"
+		}
+		*out = append(*out, fmt.Sprintf("%s: %s", loc, ve.Message))
+		return
+	}
+	for _, c := range ve.Causes {
+		collectLeaves(c, out)
+	}
+}
@@ -2,16 +2,33 @@ package ssm
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"golang.org/x/time/rate"
 )
 
+// ErrNoRegion is returned by NewClient when no AWS region can be resolved
+var ErrNoRegion = errors.New("no AWS region configured; set --region, LOCKR_REGION, or AWS_REGION")
+
+// SecureStringType is the Type of encrypted parameters
+const SecureStringType = string(types.ParameterTypeSecureString)
+
+// StandardTierMaxBytes is the maximum value size of a Standard-tier parameter
+const StandardTierMaxBytes = 4096
+
 // Secret represents a secret from SSM Parameter Store
 type Secret struct {
 	Name        string
@@ -30,32 +47,235 @@ type SecretMetadata struct {
 	LastModified *time.Time `json:"last_modified,omitempty"`
 	Description  string     `json:"description,omitempty"`
 	Tier         string     `json:"tier,omitempty"`
+
+	// LastModifiedUser is only populated by DescribeSecrets/DescribeSecret
+	LastModifiedUser string `json:"last_modified_user,omitempty"`
+
+	// KeyID is the KMS key of a SecureString, only populated by
+	// DescribeSecrets/DescribeSecret
+	KeyID string `json:"kms_key_id,omitempty"`
+
+	// Policies are the parameter policies of an Advanced-tier parameter,
+	// only populated by DescribeSecrets/DescribeSecret
+	Policies []Policy `json:"policies,omitempty"`
+
+	// Tags is only populated when requested separately (see GetTags)
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Value is only populated when requested separately (see ReadSecretsByName)
+	Value string `json:"value,omitempty"`
+}
+
+// Policy is a parameter policy (Expiration, ExpirationNotification or
+// NoChangeNotification) attached to an Advanced-tier parameter
+type Policy struct {
+	Type       string            `json:"type"`
+	Status     string            `json:"status"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+
+	// Text is the raw policy JSON, kept when it can't be parsed
+	Text string `json:"text,omitempty"`
+
+	// raw is the policy JSON as SSM returned it, for writing it elsewhere
+	raw string
+}
+
+// policiesJSON joins policies back into the JSON array PutParameter expects
+func policiesJSON(policies []Policy) string {
+	raws := make([]string, len(policies))
+	for i, p := range policies {
+		raws[i] = p.raw
+	}
+	return "[" + strings.Join(raws, ",") + "]"
+}
+
+// toPolicies converts DescribeParameters policies, parsing the attributes
+// out of each policy's JSON text
+func toPolicies(inline []types.ParameterInlinePolicy) []Policy {
+	var policies []Policy
+	for _, p := range inline {
+		policy := Policy{
+			Type:   aws.ToString(p.PolicyType),
+			Status: aws.ToString(p.PolicyStatus),
+			raw:    aws.ToString(p.PolicyText),
+		}
+
+		var doc struct {
+			Attributes map[string]interface{} `json:"Attributes"`
+		}
+		if err := json.Unmarshal([]byte(aws.ToString(p.PolicyText)), &doc); err != nil {
+			policy.Text = aws.ToString(p.PolicyText)
+		} else if len(doc.Attributes) > 0 {
+			policy.Attributes = make(map[string]string, len(doc.Attributes))
+			for k, v := range doc.Attributes {
+				policy.Attributes[k] = fmt.Sprint(v)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// SecretVersion is one entry in a secret's version history
+type SecretVersion struct {
+	Version          int64      `json:"version"`
+	Value            string     `json:"value,omitempty"`
+	Type             string     `json:"type"`
+	LastModified     *time.Time `json:"last_modified,omitempty"`
+	LastModifiedUser string     `json:"last_modified_user,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Labels           []string   `json:"labels,omitempty"`
 }
 
 // Client wraps the SSM client
 type Client struct {
-	ssm *ssm.Client
+	ssm    *ssm.Client
+	awsCfg aws.Config
 }
 
 // NewClient creates a new SSM client
-func NewClient(region string) (*Client, error) {
+func NewClient(region string, options ...Option) (*Client, error) {
 	ctx := context.Background()
 
+	var o clientOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+
 	var opts []func(*config.LoadOptions) error
 	if region != "" {
 		opts = append(opts, config.WithRegion(region))
 	}
+	if o.profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(o.profile))
+	}
+	if o.configFile != "" {
+		opts = append(opts, config.WithSharedConfigFiles([]string{o.configFile}))
+	}
+	if o.credentialsFile != "" {
+		opts = append(opts, config.WithSharedCredentialsFiles([]string{o.credentialsFile}))
+	}
+	var mfaErr error
+	if o.mfaToken != nil {
+		opts = append(opts, config.WithAssumeRoleCredentialOptions(func(ao *stscreds.AssumeRoleOptions) {
+			ao.TokenProvider = func() (string, error) {
+				code, err := o.mfaToken()
+				mfaErr = err
+				return code, err
+			}
+		}))
+	}
 
 	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// Fall back to the instance metadata region when running on EC2
+	if cfg.Region == "" {
+		cfg.Region = metadataRegion(ctx, cfg)
+		if cfg.Region == "" {
+			return nil, ErrNoRegion
+		}
+	}
+
+	if o.credentialCache != "" && cfg.Credentials != nil {
+		file, err := credentialCacheFile(ctx, o.credentialCache, o)
+		if err != nil {
+			return nil, err
+		}
+		if file != "" {
+			cfg.Credentials = aws.NewCredentialsCache(&fileCacheProvider{path: file, next: cfg.Credentials})
+		}
+	}
+
+	for _, role := range o.roleChain {
+		cfg.Credentials = assumeRole(cfg, role)
+	}
+
+	// Resolve credentials now so an MFA prompt happens here, not in the
+	// middle of the first API call. Other credential errors surface on that
+	// call as usual.
+	if o.mfaToken != nil && cfg.Credentials != nil {
+		if _, err := cfg.Credentials.Retrieve(ctx); err != nil && mfaErr != nil {
+			return nil, fmt.Errorf("failed to read MFA code: %w", mfaErr)
+		}
+	}
+
+	var ssmOpts []func(*ssm.Options)
+	if o.rateLimit > 0 {
+		// Shared by every call this client makes, including paginators
+		limiter := rate.NewLimiter(rate.Limit(o.rateLimit), 1)
+		ssmOpts = append(ssmOpts, func(so *ssm.Options) {
+			so.APIOptions = append(so.APIOptions, rateLimitMiddleware(limiter))
+		})
+	}
+
 	return &Client{
-		ssm: ssm.NewFromConfig(cfg),
+		ssm:    ssm.NewFromConfig(cfg, ssmOpts...),
+		awsCfg: cfg,
 	}, nil
 }
 
+// assumeRole returns credentials for role, obtained with the credentials
+// currently in cfg
+func assumeRole(cfg aws.Config, role AssumeRole) aws.CredentialsProvider {
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = "lockr"
+		if role.ExternalID != "" {
+			o.ExternalID = aws.String(role.ExternalID)
+		}
+	})
+	return aws.NewCredentialsCache(provider)
+}
+
+// metadataRegion asks EC2 instance metadata for the region, returning "" if
+// it isn't reachable (i.e. not running on AWS compute). It fails fast so
+// local use isn't slowed down.
+func metadataRegion(ctx context.Context, cfg aws.Config) string {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	client := imds.NewFromConfig(cfg, func(o *imds.Options) {
+		o.Retryer = aws.NopRetryer{}
+	})
+	out, err := client.GetRegion(ctx, &imds.GetRegionInput{})
+	if err != nil {
+		return ""
+	}
+	return out.Region
+}
+
+// AWSConfig returns the resolved AWS config (credentials, region) the client uses
+func (c *Client) AWSConfig() aws.Config {
+	return c.awsCfg
+}
+
+// CheckCredentials verifies that AWS credentials can be resolved and have not
+// expired. It does not call SSM.
+func (c *Client) CheckCredentials(ctx context.Context) error {
+	if c.awsCfg.Credentials == nil {
+		return fmt.Errorf("no AWS credentials configured")
+	}
+	creds, err := c.awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+	if creds.Expired() {
+		return fmt.Errorf("AWS credentials expired at %s", creds.Expires.Local().Format(time.RFC1123))
+	}
+	return nil
+}
+
+// CallerARN returns the ARN of the identity the client's credentials belong to
+func (c *Client) CallerARN(ctx context.Context) (string, error) {
+	out, err := sts.NewFromConfig(c.awsCfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.Arn), nil
+}
+
 // WriteSecret writes a secret to SSM Parameter Store
 // Handles the AWS limitation where tags can't be set with overwrite
 func (c *Client) WriteSecret(path, value string, tags map[string]string, overwrite bool, kmsKey string) error {
@@ -104,6 +324,172 @@ func (c *Client) WriteSecret(path, value string, tags map[string]string, overwri
 	return err
 }
 
+// SetPolicies replaces the parameter policies of an existing parameter.
+// PutParameter can't change policies alone, so the current value is written
+// back unchanged (creating a new version) with the same type, KMS key and
+// description. Policies need the Advanced tier, so the parameter is moved to
+// it when policies is non-empty; "[]" removes all policies.
+func (c *Client) SetPolicies(path, policies string) error {
+	return c.rewrite(path, func(input *ssm.PutParameterInput) error {
+		input.Policies = aws.String(policies)
+		if policies != "[]" {
+			input.Tier = types.ParameterTierAdvanced
+		}
+		return nil
+	})
+}
+
+// Rekey re-encrypts a SecureString under kmsKey by writing its current value
+// back with the new key (creating a new version)
+func (c *Client) Rekey(path, kmsKey string) error {
+	return c.rewrite(path, func(input *ssm.PutParameterInput) error {
+		if input.Type != types.ParameterTypeSecureString {
+			return fmt.Errorf("%s is a %s, only SecureString parameters are encrypted", path, input.Type)
+		}
+		input.KeyId = aws.String(kmsKey)
+		return nil
+	})
+}
+
+// UpdateValue writes a new version of an existing parameter with value,
+// keeping its type, tier, KMS key and description
+func (c *Client) UpdateValue(path, value string) error {
+	return c.rewrite(path, func(input *ssm.PutParameterInput) error {
+		input.Value = aws.String(value)
+		return nil
+	})
+}
+
+// rewrite writes a parameter's current value back over itself, keeping its
+// type, tier, KMS key and description, after change has adjusted the
+// PutParameter input. An error from change aborts the write.
+func (c *Client) rewrite(path string, change func(*ssm.PutParameterInput) error) error {
+	ctx := context.Background()
+
+	current, err := c.ssm.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(path),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+	meta, err := c.DescribeSecret(path)
+	if err != nil {
+		return err
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:      aws.String(path),
+		Value:     current.Parameter.Value,
+		Type:      current.Parameter.Type,
+		Tier:      types.ParameterTier(meta.Tier),
+		Overwrite: aws.Bool(true),
+	}
+	if meta.KeyID != "" {
+		input.KeyId = aws.String(meta.KeyID)
+	}
+	if meta.Description != "" {
+		input.Description = aws.String(meta.Description)
+	}
+	if err := change(input); err != nil {
+		return err
+	}
+
+	_, err = c.ssm.PutParameter(ctx, input)
+	return err
+}
+
+// CopyOptions controls what CopySecret carries to the destination
+type CopyOptions struct {
+	// Policies also copies the source's parameter policies
+	Policies bool
+
+	// Placeholder, if set, is written instead of the source's value
+	Placeholder string
+
+	// Overwrite replaces an existing destination
+	Overwrite bool
+}
+
+// CopySecret writes src's value to dst with the same type, tier, description,
+// KMS key and tags (and policies, if asked). The destination is a new
+// parameter, so its version history starts again at 1. If src's tags can't
+// be read, nothing is written.
+func (c *Client) CopySecret(src, dst string, opts CopyOptions) error {
+	ctx := context.Background()
+
+	current, err := c.ReadSecret(src)
+	if err != nil {
+		return err
+	}
+	meta, err := c.DescribeSecret(src)
+	if err != nil {
+		return err
+	}
+	tags, err := c.GetTags(src)
+	if err != nil {
+		return fmt.Errorf("failed to read tags of %s: %w", src, err)
+	}
+
+	input := &ssm.PutParameterInput{
+		Name:  aws.String(dst),
+		Value: aws.String(current.Value),
+		Type:  types.ParameterType(meta.Type),
+		Tier:  types.ParameterTier(meta.Tier),
+	}
+	if opts.Placeholder != "" {
+		input.Value = aws.String(opts.Placeholder)
+	}
+	if meta.Description != "" {
+		input.Description = aws.String(meta.Description)
+	}
+	if meta.KeyID != "" {
+		input.KeyId = aws.String(meta.KeyID)
+	}
+	if opts.Policies && len(meta.Policies) > 0 {
+		input.Policies = aws.String(policiesJSON(meta.Policies))
+	}
+	for k, v := range tags {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	// As in WriteSecret, tags can't be sent with overwrite: create first,
+	// and only if the destination exists overwrite it and tag separately
+	_, err = c.ssm.PutParameter(ctx, input)
+	if err == nil || !IsAlreadyExists(err) || !opts.Overwrite {
+		return err
+	}
+	input.Tags = nil
+	input.Overwrite = aws.Bool(true)
+	if _, err := c.ssm.PutParameter(ctx, input); err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		return c.SetTags(dst, tags)
+	}
+	return nil
+}
+
+// Unchanged reports whether the parameter already exists as a SecureString
+// holding exactly value. A missing parameter is reported as changed.
+func (c *Client) Unchanged(path, value string) (bool, error) {
+	ctx := context.Background()
+
+	result, err := c.ssm.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(path),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return result.Parameter.Type == types.ParameterTypeSecureString &&
+		aws.ToString(result.Parameter.Value) == value, nil
+}
+
 // SetTags sets tags on a parameter (replaces existing tags with same keys)
 func (c *Client) SetTags(path string, tags map[string]string) error {
 	ctx := context.Background()
@@ -124,6 +510,64 @@ func (c *Client) SetTags(path string, tags map[string]string) error {
 	return err
 }
 
+// GetTags returns the tags on a parameter
+func (c *Client) GetTags(path string) (map[string]string, error) {
+	ctx := context.Background()
+
+	result, err := c.ssm.ListTagsForResource(ctx, &ssm.ListTagsForResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(path),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string, len(result.TagList))
+	for _, tag := range result.TagList {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// RemoveTags removes the given tag keys from a parameter
+func (c *Client) RemoveTags(path string, keys []string) error {
+	ctx := context.Background()
+
+	_, err := c.ssm.RemoveTagsFromResource(ctx, &ssm.RemoveTagsFromResourceInput{
+		ResourceType: types.ResourceTypeForTaggingParameter,
+		ResourceId:   aws.String(path),
+		TagKeys:      keys,
+	})
+	return err
+}
+
+// ReplaceTags makes the parameter's tags exactly match tags: keys that are
+// not in the new set are removed, then the new set is added. Existing keys
+// listed in keep are left as they are.
+func (c *Client) ReplaceTags(path string, tags map[string]string, keep ...string) error {
+	existing, err := c.GetTags(path)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for k := range existing {
+		if _, ok := tags[k]; !ok && !slices.Contains(keep, k) {
+			stale = append(stale, k)
+		}
+	}
+	if len(stale) > 0 {
+		if err := c.RemoveTags(path, stale); err != nil {
+			return err
+		}
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+	return c.SetTags(path, tags)
+}
+
 // ReadSecret reads a secret from SSM Parameter Store
 func (c *Client) ReadSecret(path string) (*Secret, error) {
 	ctx := context.Background()
@@ -145,37 +589,78 @@ func (c *Client) ReadSecret(path string) (*Secret, error) {
 	}
 
 	// Get tags
-	tagsResult, err := c.ssm.ListTagsForResource(ctx, &ssm.ListTagsForResourceInput{
-		ResourceType: types.ResourceTypeForTaggingParameter,
-		ResourceId:   aws.String(path),
-	})
-	if err == nil && len(tagsResult.TagList) > 0 {
-		secret.Tags = make(map[string]string)
-		for _, tag := range tagsResult.TagList {
-			secret.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
-		}
+	tags, err := c.GetTags(path)
+	if err == nil && len(tags) > 0 {
+		secret.Tags = tags
 	}
 
 	return secret, nil
 }
 
+// Filter narrows ListSecrets, WalkSecrets and DescribeSecrets to matching
+// parameters, filtered by SSM
+type Filter struct {
+	key    string
+	values []string
+}
+
+// KeyIDFilter keeps only SecureStrings encrypted with one of keys. SSM
+// compares them to the KeyId it stores (usually the alias for alias/aws/ssm,
+// the ARN for customer managed keys), so give both forms when unsure.
+func KeyIDFilter(keys ...string) Filter {
+	return Filter{key: "KeyId", values: keys}
+}
+
+// parameterFilters converts filters to SSM parameter filters
+func parameterFilters(filters []Filter) []types.ParameterStringFilter {
+	var out []types.ParameterStringFilter
+	for _, f := range filters {
+		out = append(out, types.ParameterStringFilter{
+			Key:    aws.String(f.key),
+			Option: aws.String("Equals"),
+			Values: f.values,
+		})
+	}
+	return out
+}
+
 // ListSecrets lists secrets at a path
-func (c *Client) ListSecrets(path string, recursive bool) ([]SecretMetadata, error) {
+func (c *Client) ListSecrets(path string, recursive bool, filters ...Filter) ([]SecretMetadata, error) {
+	var secrets []SecretMetadata
+	err := c.WalkSecrets(path, recursive, func(meta SecretMetadata) error {
+		secrets = append(secrets, meta)
+		return nil
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// ErrStopWalk can be returned by a WalkSecrets callback to stop the walk
+// early without an error
+var ErrStopWalk = errors.New("stop walk")
+
+// WalkSecrets calls fn for every secret at a path as each page arrives, so
+// large listings needn't be held in memory and can stop early. If fn returns
+// ErrStopWalk no more pages are fetched and WalkSecrets returns nil; any other
+// error from fn stops the walk and is returned.
+func (c *Client) WalkSecrets(path string, recursive bool, fn func(SecretMetadata) error, filters ...Filter) error {
 	ctx := context.Background()
 
 	input := &ssm.GetParametersByPathInput{
-		Path:           aws.String(path),
-		Recursive:      aws.Bool(recursive),
-		WithDecryption: aws.Bool(false), // Don't decrypt for listing
+		Path:             aws.String(path),
+		Recursive:        aws.Bool(recursive),
+		WithDecryption:   aws.Bool(false), // Don't decrypt for listing
+		ParameterFilters: parameterFilters(filters),
 	}
 
-	var secrets []SecretMetadata
 	paginator := ssm.NewGetParametersByPathPaginator(c.ssm, input)
 
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		for _, p := range page.Parameters {
@@ -187,13 +672,228 @@ func (c *Client) ListSecrets(path string, recursive bool) ([]SecretMetadata, err
 			if p.LastModifiedDate != nil {
 				meta.LastModified = p.LastModifiedDate
 			}
-			secrets = append(secrets, meta)
+			if err := fn(meta); err != nil {
+				if errors.Is(err, ErrStopWalk) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DescribeSecrets lists secret metadata at a path using DescribeParameters,
+// which includes fields GetParametersByPath doesn't return (last modified
+// user, tier, description)
+func (c *Client) DescribeSecrets(path string, recursive bool, filters ...Filter) ([]SecretMetadata, error) {
+	ctx := context.Background()
+
+	input := &ssm.DescribeParametersInput{ParameterFilters: parameterFilters(filters)}
+	if path != "/" || !recursive {
+		option := "OneLevel"
+		if recursive {
+			option = "Recursive"
+		}
+		input.ParameterFilters = append(input.ParameterFilters, types.ParameterStringFilter{
+			Key:    aws.String("Path"),
+			Option: aws.String(option),
+			Values: []string{path},
+		})
+	}
+
+	var secrets []SecretMetadata
+	paginator := ssm.NewDescribeParametersPaginator(c.ssm, input)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range page.Parameters {
+			secrets = append(secrets, SecretMetadata{
+				Name:             aws.ToString(p.Name),
+				Type:             string(p.Type),
+				Version:          p.Version,
+				LastModified:     p.LastModifiedDate,
+				Description:      aws.ToString(p.Description),
+				Tier:             string(p.Tier),
+				LastModifiedUser: aws.ToString(p.LastModifiedUser),
+				KeyID:            aws.ToString(p.KeyId),
+				Policies:         toPolicies(p.Policies),
+			})
+		}
+	}
+
+	return secrets, nil
+}
+
+// DescribeSecret returns the DescribeParameters metadata for a single secret.
+// Like Exists, it follows NextToken and retries throttling.
+func (c *Client) DescribeSecret(path string) (*SecretMetadata, error) {
+	p, err := c.describeByName(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, &types.ParameterNotFound{Message: aws.String(path + " not found")}
+	}
+
+	return &SecretMetadata{
+		Name:             aws.ToString(p.Name),
+		Type:             string(p.Type),
+		Version:          p.Version,
+		LastModified:     p.LastModifiedDate,
+		Description:      aws.ToString(p.Description),
+		Tier:             string(p.Tier),
+		LastModifiedUser: aws.ToString(p.LastModifiedUser),
+		KeyID:            aws.ToString(p.KeyId),
+		Policies:         toPolicies(p.Policies),
+	}, nil
+}
+
+// ReadSecrets reads all secrets (with decrypted values) at a path.
+// Tags are not fetched.
+func (c *Client) ReadSecrets(path string, recursive bool) ([]Secret, error) {
+	ctx := context.Background()
+
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(path),
+		Recursive:      aws.Bool(recursive),
+		WithDecryption: aws.Bool(true),
+	}
+
+	var secrets []Secret
+	paginator := ssm.NewGetParametersByPathPaginator(c.ssm, input)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range page.Parameters {
+			secrets = append(secrets, Secret{
+				Name:    aws.ToString(p.Name),
+				Value:   aws.ToString(p.Value),
+				Type:    string(p.Type),
+				Version: p.Version,
+			})
+		}
+	}
+
+	return secrets, nil
+}
+
+// getParametersMax is the most names a single GetParameters call accepts
+const getParametersMax = 10
+
+// ReadSecretsByName reads the decrypted values of the named parameters in
+// batches. Names that don't exist are left out of the result.
+func (c *Client) ReadSecretsByName(names []string) ([]Secret, error) {
+	ctx := context.Background()
+
+	var secrets []Secret
+	for start := 0; start < len(names); start += getParametersMax {
+		end := min(start+getParametersMax, len(names))
+		result, err := c.ssm.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          names[start:end],
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range result.Parameters {
+			secrets = append(secrets, Secret{
+				Name:    aws.ToString(p.Name),
+				Value:   aws.ToString(p.Value),
+				Type:    string(p.Type),
+				Version: p.Version,
+			})
 		}
 	}
 
 	return secrets, nil
 }
 
+// CurrentVersions returns the current version of each named parameter,
+// looked up in batches without decrypting values. Names that don't exist are
+// left out of the result.
+func (c *Client) CurrentVersions(names []string) (map[string]int64, error) {
+	ctx := context.Background()
+
+	versions := make(map[string]int64, len(names))
+	for start := 0; start < len(names); start += getParametersMax {
+		end := min(start+getParametersMax, len(names))
+		result, err := c.ssm.GetParameters(ctx, &ssm.GetParametersInput{
+			Names: names[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range result.Parameters {
+			versions[aws.ToString(p.Name)] = p.Version
+		}
+	}
+
+	return versions, nil
+}
+
+// History returns every version of a secret, oldest first. Values are only
+// populated when withDecryption is true.
+func (c *Client) History(path string, withDecryption bool) ([]SecretVersion, error) {
+	ctx := context.Background()
+
+	input := &ssm.GetParameterHistoryInput{
+		Name:           aws.String(path),
+		WithDecryption: aws.Bool(withDecryption),
+	}
+
+	var versions []SecretVersion
+	paginator := ssm.NewGetParameterHistoryPaginator(c.ssm, input)
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range page.Parameters {
+			v := SecretVersion{
+				Version:          p.Version,
+				Type:             string(p.Type),
+				LastModified:     p.LastModifiedDate,
+				LastModifiedUser: aws.ToString(p.LastModifiedUser),
+				Description:      aws.ToString(p.Description),
+				Labels:           p.Labels,
+			}
+			if withDecryption {
+				v.Value = aws.ToString(p.Value)
+			}
+			versions = append(versions, v)
+		}
+	}
+
+	return versions, nil
+}
+
+// LabelVersion attaches labels to a specific version of a secret. A label
+// already on another version is moved.
+func (c *Client) LabelVersion(path string, version int64, labels ...string) error {
+	ctx := context.Background()
+
+	_, err := c.ssm.LabelParameterVersion(ctx, &ssm.LabelParameterVersionInput{
+		Name:             aws.String(path),
+		ParameterVersion: aws.Int64(version),
+		Labels:           labels,
+	})
+	return err
+}
+
 // DeleteSecret deletes a secret from SSM Parameter Store
 func (c *Client) DeleteSecret(path string) error {
 	ctx := context.Background()
@@ -204,20 +904,153 @@ func (c *Client) DeleteSecret(path string) error {
 	return err
 }
 
-// Exists checks if a parameter exists
-func (c *Client) Exists(path string) (bool, error) {
+// deleteParametersMax is the most names a single DeleteParameters call accepts
+const deleteParametersMax = 10
+
+// DeleteSecrets deletes the named parameters in batches. It returns the names
+// that were deleted and an error for each name that wasn't: names that don't
+// exist, and every name in a batch whose request failed.
+func (c *Client) DeleteSecrets(names []string) ([]string, map[string]error) {
 	ctx := context.Background()
 
-	_, err := c.ssm.GetParameter(ctx, &ssm.GetParameterInput{
-		Name:           aws.String(path),
-		WithDecryption: aws.Bool(false),
-	})
-	if err != nil {
-		var pnf *types.ParameterNotFound
-		if errors.As(err, &pnf) {
-			return false, nil
+	var deleted []string
+	failed := make(map[string]error)
+	for start := 0; start < len(names); start += deleteParametersMax {
+		end := min(start+deleteParametersMax, len(names))
+		result, err := c.ssm.DeleteParameters(ctx, &ssm.DeleteParametersInput{
+			Names: names[start:end],
+		})
+		if err != nil {
+			for _, name := range names[start:end] {
+				failed[name] = err
+			}
+			continue
+		}
+
+		deleted = append(deleted, result.DeletedParameters...)
+		for _, name := range result.InvalidParameters {
+			failed[name] = &types.ParameterNotFound{Message: aws.String(name + " not found")}
 		}
+	}
+
+	return deleted, failed
+}
+
+// existsAttempts is how often Exists and DescribeSecret try a page when SSM
+// keeps throttling them, on top of the SDK's own retries
+const existsAttempts = 3
+
+// existsBackoff is the pause before the first retry of a throttled page,
+// doubled after each (a var so tests needn't wait)
+var existsBackoff = 500 * time.Millisecond
+
+// Exists checks if a parameter exists. It uses DescribeParameters, which
+// never touches the value or its KMS key, so it works with metadata-only
+// permissions (ssm:DescribeParameters). SSM applies the filter page by page
+// and may return an empty page with a NextToken, so pages are followed until
+// the parameter turns up or there are none left. Throttling is retried with
+// backoff; any other error (e.g. access denied) is returned rather than being
+// reported as "doesn't exist".
+func (c *Client) Exists(path string) (bool, error) {
+	p, err := c.describeByName(context.Background(), path)
+	if err != nil {
 		return false, err
 	}
-	return true, nil
+	return p != nil, nil
+}
+
+// describeByName returns the DescribeParameters metadata for the parameter
+// named path, or nil if there is none. Pages are followed until it turns up
+// or there are none left, and throttled pages are retried.
+func (c *Client) describeByName(ctx context.Context, path string) (*types.ParameterMetadata, error) {
+	input := &ssm.DescribeParametersInput{
+		ParameterFilters: []types.ParameterStringFilter{{
+			Key:    aws.String("Name"),
+			Option: aws.String("Equals"),
+			Values: []string{path},
+		}},
+	}
+
+	for {
+		result, err := c.describeParametersRetrying(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Parameters) > 0 {
+			return &result.Parameters[0], nil
+		}
+		if aws.ToString(result.NextToken) == "" {
+			return nil, nil
+		}
+		input.NextToken = result.NextToken
+	}
+}
+
+// describeParametersRetrying calls DescribeParameters, retrying throttling
+// up to existsAttempts times
+func (c *Client) describeParametersRetrying(ctx context.Context, input *ssm.DescribeParametersInput) (*ssm.DescribeParametersOutput, error) {
+	backoff := existsBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := c.ssm.DescribeParameters(ctx, input)
+		if err == nil {
+			return result, nil
+		}
+		if !IsThrottled(err) || attempt == existsAttempts {
+			return nil, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// IsThrottled reports whether err is SSM rejecting a request for exceeding
+// its API rate. Such requests can be retried after a pause.
+func IsThrottled(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "ThrottlingException", "TooManyUpdates", "RequestLimitExceeded":
+		return true
+	}
+	return false
+}
+
+// IsAccessDenied reports whether err is an AWS API refusing a request for
+// lack of permission (e.g. kms:DescribeKey)
+func IsAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "AccessDeniedException", "AccessDenied":
+		return true
+	}
+	return false
+}
+
+// IsNotFound reports whether err is an SSM parameter-not-found error
+func IsNotFound(err error) bool {
+	var pnf *types.ParameterNotFound
+	return errors.As(err, &pnf)
+}
+
+// IsAlreadyExists reports whether err is an SSM parameter-already-exists
+// error, as returned by a write without overwrite
+func IsAlreadyExists(err error) bool {
+	var pae *types.ParameterAlreadyExists
+	return errors.As(err, &pae)
+}
+
+// IsEmptyValueRejected reports whether err is SSM refusing to store an empty
+// value (SecureString parameters must be at least one character long)
+func IsEmptyValueRejected(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		return false
+	}
+	msg := apiErr.ErrorMessage()
+	return strings.Contains(msg, "'value'") && strings.Contains(msg, "length")
 }
@@ -0,0 +1,201 @@
+package ssm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// stubResponse is one canned SSM API response
+type stubResponse struct {
+	status int
+	body   string
+}
+
+// stubSSM answers SSM API calls with canned responses, in order, and records
+// the request bodies
+type stubSSM struct {
+	responses []stubResponse
+	requests  []string
+}
+
+func (s *stubSSM) Do(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.requests = append(s.requests, string(body))
+
+	resp := stubResponse{status: http.StatusInternalServerError, body: `{"__type":"UnexpectedCall"}`}
+	if len(s.requests) <= len(s.responses) {
+		resp = s.responses[len(s.requests)-1]
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(strings.NewReader(resp.body)),
+		Request:    req,
+	}, nil
+}
+
+// newStubClient returns a Client whose SSM calls are answered by stub, with
+// the SDK's own retries off so only Exists' retries are exercised
+func newStubClient(stub *stubSSM) *Client {
+	return &Client{ssm: ssm.New(ssm.Options{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:  stub,
+		Retryer:     aws.NopRetryer{},
+	})}
+}
+
+func TestExists(t *testing.T) {
+	old := existsBackoff
+	existsBackoff = 0
+	t.Cleanup(func() { existsBackoff = old })
+
+	found := stubResponse{http.StatusOK, `{"Parameters":[{"Name":"/app/key"}]}`}
+	empty := stubResponse{http.StatusOK, `{"Parameters":[]}`}
+	emptyWithToken := stubResponse{http.StatusOK, `{"Parameters":[],"NextToken":"page2"}`}
+	throttled := stubResponse{http.StatusBadRequest, `{"__type":"ThrottlingException","message":"Rate exceeded"}`}
+	denied := stubResponse{http.StatusBadRequest, `{"__type":"AccessDeniedException","message":"not authorized"}`}
+
+	tests := []struct {
+		name          string
+		responses     []stubResponse
+		want          bool
+		wantErr       bool
+		wantThrottled bool
+		wantCalls     int
+
+		// wantTokens, if set, are the NextToken each request should send
+		wantTokens []string
+	}{
+		{name: "exists", responses: []stubResponse{found}, want: true, wantCalls: 1},
+		{name: "not found", responses: []stubResponse{empty}, want: false, wantCalls: 1},
+		{name: "found on a later page", responses: []stubResponse{emptyWithToken, found}, want: true, wantCalls: 2, wantTokens: []string{"", "page2"}},
+		{name: "not found on any page", responses: []stubResponse{emptyWithToken, empty}, want: false, wantCalls: 2},
+		{name: "throttled then exists", responses: []stubResponse{throttled, found}, want: true, wantCalls: 2},
+		{name: "throttled then error", responses: []stubResponse{throttled, denied}, wantErr: true, wantCalls: 2},
+		{name: "throttled on every attempt", responses: []stubResponse{throttled, throttled, throttled}, wantErr: true, wantThrottled: true, wantCalls: existsAttempts},
+		{name: "other errors aren't retried", responses: []stubResponse{denied}, wantErr: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &stubSSM{responses: tt.responses}
+
+			got, err := newStubClient(stub).Exists("/app/key")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Exists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && IsThrottled(err) != tt.wantThrottled {
+				t.Errorf("IsThrottled(%v) = %v, want %v", err, !tt.wantThrottled, tt.wantThrottled)
+			}
+			if got != tt.want {
+				t.Errorf("Exists() = %v, want %v", got, tt.want)
+			}
+			if len(stub.requests) != tt.wantCalls {
+				t.Fatalf("DescribeParameters called %d times, want %d", len(stub.requests), tt.wantCalls)
+			}
+
+			for i, want := range tt.wantTokens {
+				var input struct {
+					NextToken        string
+					ParameterFilters []struct {
+						Key    string
+						Option string
+						Values []string
+					}
+				}
+				if err := json.Unmarshal([]byte(stub.requests[i]), &input); err != nil {
+					t.Fatalf("request %d: %v", i+1, err)
+				}
+				if input.NextToken != want {
+					t.Errorf("request %d NextToken = %q, want %q", i+1, input.NextToken, want)
+				}
+				if len(input.ParameterFilters) != 1 || input.ParameterFilters[0].Key != "Name" ||
+					input.ParameterFilters[0].Option != "Equals" || strings.Join(input.ParameterFilters[0].Values, ",") != "/app/key" {
+					t.Errorf("request %d filters = %+v, want Name Equals /app/key", i+1, input.ParameterFilters)
+				}
+			}
+		})
+	}
+}
+
+func TestDescribeSecret(t *testing.T) {
+	old := existsBackoff
+	existsBackoff = 0
+	t.Cleanup(func() { existsBackoff = old })
+
+	tests := []struct {
+		name         string
+		responses    []stubResponse
+		wantName     string
+		wantNotFound bool
+	}{
+		{
+			name:      "first page",
+			responses: []stubResponse{{http.StatusOK, `{"Parameters":[{"Name":"/app/key","Version":3}]}`}},
+			wantName:  "/app/key",
+		},
+		{
+			name: "later page after throttling",
+			responses: []stubResponse{
+				{http.StatusOK, `{"Parameters":[],"NextToken":"page2"}`},
+				{http.StatusBadRequest, `{"__type":"ThrottlingException","message":"Rate exceeded"}`},
+				{http.StatusOK, `{"Parameters":[{"Name":"/app/key","Version":3}]}`},
+			},
+			wantName: "/app/key",
+		},
+		{
+			name: "not found on any page",
+			responses: []stubResponse{
+				{http.StatusOK, `{"Parameters":[],"NextToken":"page2"}`},
+				{http.StatusOK, `{"Parameters":[]}`},
+			},
+			wantNotFound: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newStubClient(&stubSSM{responses: tt.responses}).DescribeSecret("/app/key")
+			if tt.wantNotFound {
+				if !IsNotFound(err) {
+					t.Fatalf("DescribeSecret() error = %v, want ParameterNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DescribeSecret() error = %v", err)
+			}
+			if got.Name != tt.wantName || got.Version != 3 {
+				t.Errorf("DescribeSecret() = %s v%d, want %s v3", got.Name, got.Version, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestCopySecretFailsWhenTagsCantBeRead(t *testing.T) {
+	denied := stubResponse{http.StatusBadRequest, `{"__type":"AccessDeniedException","message":"not authorized"}`}
+	stub := &stubSSM{responses: []stubResponse{
+		{http.StatusOK, `{"Parameter":{"Name":"/app/key","Value":"v","Type":"SecureString","Version":1}}`},
+		denied, // ListTagsForResource in ReadSecret
+		{http.StatusOK, `{"Parameters":[{"Name":"/app/key","Type":"SecureString","Tier":"Standard"}]}`},
+		denied, // ListTagsForResource in CopySecret
+	}}
+
+	if err := newStubClient(stub).CopySecret("/app/key", "/app/copy", CopyOptions{}); err == nil {
+		t.Fatal("CopySecret() error = nil, want the ListTagsForResource error")
+	}
+	if len(stub.requests) != 4 {
+		t.Errorf("made %d calls, want 4 (nothing written)", len(stub.requests))
+	}
+}
@@ -0,0 +1,134 @@
+package ssm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// credentialCacheMargin is how long before expiry cached credentials stop
+// being reused, so a command doesn't start with a session about to lapse
+const credentialCacheMargin = 5 * time.Minute
+
+// cachedCredentials is the on-disk form of a cached session
+type cachedCredentials struct {
+	AccessKeyID     string    `json:"access_key_id"`
+	SecretAccessKey string    `json:"secret_access_key"`
+	SessionToken    string    `json:"session_token"`
+	Expires         time.Time `json:"expires"`
+}
+
+// fileCacheProvider reuses assumed-role credentials stored in a file until
+// they're close to expiry, and otherwise fetches fresh ones from next and
+// stores them
+type fileCacheProvider struct {
+	path string
+	next aws.CredentialsProvider
+}
+
+func (p *fileCacheProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if data, err := os.ReadFile(p.path); err == nil {
+		var c cachedCredentials
+		if json.Unmarshal(data, &c) == nil && time.Until(c.Expires) > credentialCacheMargin {
+			return aws.Credentials{
+				AccessKeyID:     c.AccessKeyID,
+				SecretAccessKey: c.SecretAccessKey,
+				SessionToken:    c.SessionToken,
+				Source:          "lockr credential cache",
+				CanExpire:       true,
+				Expires:         c.Expires,
+			}, nil
+		}
+	}
+
+	creds, err := p.next.Retrieve(ctx)
+	if err != nil {
+		return creds, err
+	}
+	if creds.CanExpire {
+		// Best-effort: a cache that can't be written just means the next
+		// run assumes the role again
+		_ = p.store(creds)
+	}
+	return creds, nil
+}
+
+// store writes creds to the cache file with 0600 permissions, replacing it
+// atomically
+func (p *fileCacheProvider) store(creds aws.Credentials) error {
+	data, err := json.Marshal(cachedCredentials{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		Expires:         creds.Expires,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), ".creds-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p.path)
+}
+
+// credentialCacheFile returns the cache file for the active profile's
+// assumed role, or "" if the profile doesn't assume a role (its credentials
+// are already long-lived or managed elsewhere)
+func credentialCacheFile(ctx context.Context, dir string, o clientOptions) (string, error) {
+	env, err := config.NewEnvConfig()
+	if err != nil {
+		return "", err
+	}
+	profile := o.profile
+	if profile == "" {
+		profile = env.SharedConfigProfile
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(so *config.LoadSharedConfigOptions) {
+		if o.configFile != "" {
+			so.ConfigFiles = []string{o.configFile}
+		}
+		if o.credentialsFile != "" {
+			so.CredentialsFiles = []string{o.credentialsFile}
+		}
+	})
+	if err != nil {
+		var notExist config.SharedConfigProfileNotExistError
+		if errors.As(err, &notExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read AWS profile %s: %w", profile, err)
+	}
+	if shared.RoleARN == "" {
+		return "", nil
+	}
+
+	sum := sha256.Sum256([]byte(profile + "\x00" + shared.RoleARN))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"), nil
+}
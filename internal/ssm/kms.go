@@ -0,0 +1,39 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+// KMSKey identifies a KMS key by both its key ID and ARN
+type KMSKey struct {
+	KeyID string
+	ARN   string
+}
+
+// Matches reports whether keyID (an alias, key ID or ARN, as DescribeParameters
+// reports it) refers to this key
+func (k *KMSKey) Matches(keyID string) bool {
+	return keyID != "" && (keyID == k.KeyID || keyID == k.ARN)
+}
+
+// ResolveKMSKey resolves a KMS key alias, ID or ARN to the key it refers to.
+// It needs kms:DescribeKey.
+func (c *Client) ResolveKMSKey(ctx context.Context, key string) (*KMSKey, error) {
+	out, err := kms.NewFromConfig(c.awsCfg).DescribeKey(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DescribeKey %s failed: %w", key, err)
+	}
+	if out.KeyMetadata == nil {
+		return nil, fmt.Errorf("DescribeKey %s returned no key", key)
+	}
+	return &KMSKey{
+		KeyID: aws.ToString(out.KeyMetadata.KeyId),
+		ARN:   aws.ToString(out.KeyMetadata.Arn),
+	}, nil
+}
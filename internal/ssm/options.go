@@ -0,0 +1,79 @@
+package ssm
+
+// Option configures optional Client behaviour
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	rateLimit       float64
+	configFile      string
+	credentialsFile string
+	mfaToken        func() (string, error)
+	credentialCache string
+	roleChain       []AssumeRole
+	profile         string
+}
+
+// AssumeRole is one hop in a role chain
+type AssumeRole struct {
+	RoleARN    string
+	ExternalID string // optional
+}
+
+// WithRateLimit caps SSM API requests made by the client at rps requests per
+// second. Zero (the default) means unlimited.
+func WithRateLimit(rps float64) Option {
+	return func(o *clientOptions) {
+		o.rateLimit = rps
+	}
+}
+
+// WithSharedConfigFile loads the AWS shared config from path instead of
+// ~/.aws/config. Empty means the SDK default.
+func WithSharedConfigFile(path string) Option {
+	return func(o *clientOptions) {
+		o.configFile = path
+	}
+}
+
+// WithSharedCredentialsFile loads AWS shared credentials from path instead of
+// ~/.aws/credentials. Empty means the SDK default.
+func WithSharedCredentialsFile(path string) Option {
+	return func(o *clientOptions) {
+		o.credentialsFile = path
+	}
+}
+
+// WithMFATokenProvider sets the function asked for an MFA code when the AWS
+// profile assumes a role that requires MFA (mfa_serial). Without it, such
+// profiles fail to load credentials.
+func WithMFATokenProvider(provider func() (string, error)) Option {
+	return func(o *clientOptions) {
+		o.mfaToken = provider
+	}
+}
+
+// WithCredentialCache caches assumed-role credentials in dir (0600 files keyed
+// by profile and role ARN) and reuses them until shortly before they expire,
+// so repeated runs don't assume the role, or prompt for MFA, every time.
+// Empty disables caching.
+func WithCredentialCache(dir string) Option {
+	return func(o *clientOptions) {
+		o.credentialCache = dir
+	}
+}
+
+// WithRoleChain assumes each role in order after loading the base
+// credentials, every hop using the credentials of the one before it
+func WithRoleChain(roles []AssumeRole) Option {
+	return func(o *clientOptions) {
+		o.roleChain = roles
+	}
+}
+
+// WithProfile uses the named AWS shared config profile instead of the
+// default (AWS_PROFILE). Empty means the default.
+func WithProfile(name string) Option {
+	return func(o *clientOptions) {
+		o.profile = name
+	}
+}
@@ -0,0 +1,24 @@
+package ssm
+
+import (
+	"context"
+
+	"github.com/aws/smithy-go/middleware"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitMiddleware returns an API option that makes every request attempt
+// (including retries) wait on limiter before it's sent
+func rateLimitMiddleware(limiter *rate.Limiter) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		mw := middleware.FinalizeMiddlewareFunc("lockrRateLimit", func(
+			ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler,
+		) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			if err := limiter.Wait(ctx); err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+			return next.HandleFinalize(ctx, in)
+		})
+		return stack.Finalize.Insert(mw, "Retry", middleware.After)
+	}
+}